@@ -0,0 +1,328 @@
+package bytepool
+
+import (
+  "encoding/binary"
+  "errors"
+  "io"
+  "math"
+)
+
+// returned when accessing a part of an item outside of its content
+var ErrOutOfRange = errors.New("bytepool: out of range")
+
+var (
+  errVarintWidth    = errors.New("bytepool: value doesn't fit in the varint's width")
+  errVarintOverflow = errors.New("bytepool: varint overflows a 64-bit integer")
+)
+
+// A region of an item, reserved by Reserve, to be filled in once its value is
+// known, e.g. the length prefix of a frame whose body is written after it
+//    item: the item the region belongs to
+//    offset: start of the region within the item
+//    length: size of the region
+type Reservation struct {
+  item   *Item
+  offset int
+  length int
+}
+
+// reserve the next n bytes (zeroed) of the item, nothing is reserved and
+// io.ErrShortWrite is returned when they don't fit
+func (item *Item) Reserve(n int) (Reservation, error) {
+  if n < 0 || n > item.Available() {
+    return Reservation{}, io.ErrShortWrite
+  }
+  offset := item.length
+  clear(item.bytes[offset : offset+n])
+  item.length += n
+  return Reservation{item: item, offset: offset, length: n}, nil
+}
+
+// start of the reserved region within the item
+func (r Reservation) Offset() int {
+  return r.offset
+}
+
+// size of the reserved region
+func (r Reservation) Len() int {
+  return r.length
+}
+
+// the reserved region
+func (r Reservation) Bytes() []byte {
+  return r.item.bytes[r.offset : r.offset+r.length]
+}
+
+func (r Reservation) PutUint16(order binary.ByteOrder, v uint16) error {
+  if r.length < 2 {
+    return ErrOutOfRange
+  }
+  return r.item.PutUint16At(r.offset, order, v)
+}
+
+func (r Reservation) PutUint32(order binary.ByteOrder, v uint32) error {
+  if r.length < 4 {
+    return ErrOutOfRange
+  }
+  return r.item.PutUint32At(r.offset, order, v)
+}
+
+func (r Reservation) PutUint64(order binary.ByteOrder, v uint64) error {
+  if r.length < 8 {
+    return ErrOutOfRange
+  }
+  return r.item.PutUint64At(r.offset, order, v)
+}
+
+// write v as a varint padded to the full size of the region
+func (r Reservation) PutUvarint(v uint64) error {
+  return r.item.PutUvarintAt(r.offset, r.length, v)
+}
+
+// the part of the content in [offset:offset+n], or ErrOutOfRange
+func (item *Item) at(offset, n int) ([]byte, error) {
+  if offset < 0 || offset+n > item.length {
+    return nil, ErrOutOfRange
+  }
+  item.rehash(offset)
+  return item.bytes[offset : offset+n], nil
+}
+
+// overwrite the 2 bytes of content at offset with v
+func (item *Item) PutUint16At(offset int, order binary.ByteOrder, v uint16) error {
+  b, err := item.at(offset, 2)
+  if err != nil {
+    return err
+  }
+  order.PutUint16(b, v)
+  return nil
+}
+
+// overwrite the 4 bytes of content at offset with v
+func (item *Item) PutUint32At(offset int, order binary.ByteOrder, v uint32) error {
+  b, err := item.at(offset, 4)
+  if err != nil {
+    return err
+  }
+  order.PutUint32(b, v)
+  return nil
+}
+
+// overwrite the 8 bytes of content at offset with v
+func (item *Item) PutUint64At(offset int, order binary.ByteOrder, v uint64) error {
+  b, err := item.at(offset, 8)
+  if err != nil {
+    return err
+  }
+  order.PutUint64(b, v)
+  return nil
+}
+
+// overwrite width bytes of content at offset with v encoded as a varint
+// padded to exactly width bytes (which binary.Uvarint decodes)
+func (item *Item) PutUvarintAt(offset, width int, v uint64) error {
+  if width < 1 || width > binary.MaxVarintLen64 {
+    return errVarintWidth
+  }
+  if width < binary.MaxVarintLen64 && v >= 1<<(7*width) {
+    return errVarintWidth
+  }
+  b, err := item.at(offset, width)
+  if err != nil {
+    return err
+  }
+  last := width - 1
+  for i := 0; i < last; i++ {
+    b[i] = byte(v) | 0x80
+    v >>= 7
+  }
+  b[last] = byte(v)
+  return nil
+}
+
+// the next n bytes of the slice, which become part of the content
+// or io.ErrShortWrite when they don't fit
+func (item *Item) grab(n int) ([]byte, error) {
+  if n > item.Available() {
+    return nil, io.ErrShortWrite
+  }
+  b := item.bytes[item.length : item.length+n]
+  item.length += n
+  return b, nil
+}
+
+// the next n unread bytes, which become read, or io.EOF when
+// drained and io.ErrUnexpectedEOF when fewer than n are unread
+func (item *Item) take(n int) ([]byte, error) {
+  item.lastRead = 0
+  unread := item.length - item.read
+  if unread == 0 {
+    return nil, io.EOF
+  }
+  if unread < n {
+    return nil, io.ErrUnexpectedEOF
+  }
+  b := item.bytes[item.read : item.read+n]
+  item.read += n
+  return b, nil
+}
+
+// The fixed-width Write* methods write nothing and return io.ErrShortWrite
+// when the value doesn't fit. The Read* methods read from the read cursor
+// and don't move it when they fail
+
+func (item *Item) WriteUint16(order binary.ByteOrder, v uint16) error {
+  b, err := item.grab(2)
+  if err != nil {
+    return err
+  }
+  order.PutUint16(b, v)
+  return nil
+}
+
+func (item *Item) WriteUint32(order binary.ByteOrder, v uint32) error {
+  b, err := item.grab(4)
+  if err != nil {
+    return err
+  }
+  order.PutUint32(b, v)
+  return nil
+}
+
+func (item *Item) WriteUint64(order binary.ByteOrder, v uint64) error {
+  b, err := item.grab(8)
+  if err != nil {
+    return err
+  }
+  order.PutUint64(b, v)
+  return nil
+}
+
+func (item *Item) WriteInt16(order binary.ByteOrder, v int16) error {
+  return item.WriteUint16(order, uint16(v))
+}
+
+func (item *Item) WriteInt32(order binary.ByteOrder, v int32) error {
+  return item.WriteUint32(order, uint32(v))
+}
+
+func (item *Item) WriteInt64(order binary.ByteOrder, v int64) error {
+  return item.WriteUint64(order, uint64(v))
+}
+
+// write v in IEEE 754 binary representation
+func (item *Item) WriteFloat32(order binary.ByteOrder, v float32) error {
+  return item.WriteUint32(order, math.Float32bits(v))
+}
+
+// write v in IEEE 754 binary representation
+func (item *Item) WriteFloat64(order binary.ByteOrder, v float64) error {
+  return item.WriteUint64(order, math.Float64bits(v))
+}
+
+// write v as a varint (see binary.PutUvarint)
+func (item *Item) WriteUvarint(v uint64) error {
+  var encoded [binary.MaxVarintLen64]byte
+  n := binary.PutUvarint(encoded[:], v)
+  b, err := item.grab(n)
+  if err != nil {
+    return err
+  }
+  copy(b, encoded[:n])
+  return nil
+}
+
+// write v as a zig-zag encoded varint (see binary.PutVarint)
+func (item *Item) WriteVarint(v int64) error {
+  var encoded [binary.MaxVarintLen64]byte
+  n := binary.PutVarint(encoded[:], v)
+  b, err := item.grab(n)
+  if err != nil {
+    return err
+  }
+  copy(b, encoded[:n])
+  return nil
+}
+
+func (item *Item) ReadUint16(order binary.ByteOrder) (uint16, error) {
+  b, err := item.take(2)
+  if err != nil {
+    return 0, err
+  }
+  return order.Uint16(b), nil
+}
+
+func (item *Item) ReadUint32(order binary.ByteOrder) (uint32, error) {
+  b, err := item.take(4)
+  if err != nil {
+    return 0, err
+  }
+  return order.Uint32(b), nil
+}
+
+func (item *Item) ReadUint64(order binary.ByteOrder) (uint64, error) {
+  b, err := item.take(8)
+  if err != nil {
+    return 0, err
+  }
+  return order.Uint64(b), nil
+}
+
+func (item *Item) ReadInt16(order binary.ByteOrder) (int16, error) {
+  v, err := item.ReadUint16(order)
+  return int16(v), err
+}
+
+func (item *Item) ReadInt32(order binary.ByteOrder) (int32, error) {
+  v, err := item.ReadUint32(order)
+  return int32(v), err
+}
+
+func (item *Item) ReadInt64(order binary.ByteOrder) (int64, error) {
+  v, err := item.ReadUint64(order)
+  return int64(v), err
+}
+
+func (item *Item) ReadFloat32(order binary.ByteOrder) (float32, error) {
+  v, err := item.ReadUint32(order)
+  return math.Float32frombits(v), err
+}
+
+func (item *Item) ReadFloat64(order binary.ByteOrder) (float64, error) {
+  v, err := item.ReadUint64(order)
+  return math.Float64frombits(v), err
+}
+
+// read a varint (see binary.Uvarint)
+func (item *Item) ReadUvarint() (uint64, error) {
+  v, n := binary.Uvarint(item.bytes[item.read:item.length])
+  if err := item.varintRead(n); err != nil {
+    return 0, err
+  }
+  return v, nil
+}
+
+// read a zig-zag encoded varint (see binary.Varint)
+func (item *Item) ReadVarint() (int64, error) {
+  v, n := binary.Varint(item.bytes[item.read:item.length])
+  if err := item.varintRead(n); err != nil {
+    return 0, err
+  }
+  return v, nil
+}
+
+// advance the read cursor past a varint of n bytes, as returned by binary.Uvarint
+func (item *Item) varintRead(n int) error {
+  item.lastRead = 0
+  if n < 0 {
+    return errVarintOverflow
+  }
+  if n == 0 {
+    if item.Drained() {
+      return io.EOF
+    }
+    return io.ErrUnexpectedEOF
+  }
+  item.read += n
+  return nil
+}
@@ -0,0 +1,167 @@
+package bytepool
+
+import (
+  "encoding/binary"
+  . "gopkg.in/check.v1"
+  "io"
+)
+
+func (s *TestSuite) TestReserveAndBackpatchALengthPrefix(c *C) {
+  item := newItem(20, nil)
+  header, err := item.Reserve(4)
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+
+  item.WriteString("over 9000")
+  header.PutUint32(binary.BigEndian, uint32(item.Len()-header.Len()))
+
+  expected := "\x00\x00\x00\x09over 9000"
+  c.Assert(item.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, item.String()))
+  c.Assert(header.Offset(), Equals, 0, Commentf("Expecting an offset of 0, got %d", header.Offset()))
+}
+
+func (s *TestSuite) TestReserveFailsWhenItDoesNotFit(c *C) {
+  item := newItem(4, nil)
+  item.WriteString("ab")
+  _, err := item.Reserve(3)
+
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  c.Assert(item.Len(), Equals, 2, Commentf("Expecting a length of 2, got %d", item.Len()))
+}
+
+func (s *TestSuite) TestReserveZeroesTheRegion(c *C) {
+  item := newItem(4, nil)
+  item.WriteString("abcd")
+  item.Position(0)
+  r, _ := item.Reserve(4)
+
+  c.Assert(r.Bytes(), DeepEquals, []byte{0, 0, 0, 0})
+}
+
+func (s *TestSuite) TestReservationRejectsValuesLargerThanTheRegion(c *C) {
+  item := newItem(20, nil)
+  r, _ := item.Reserve(2)
+
+  c.Assert(r.PutUint16(binary.LittleEndian, 1), IsNil)
+  c.Assert(r.PutUint32(binary.LittleEndian, 1), Equals, ErrOutOfRange)
+  c.Assert(r.PutUint64(binary.LittleEndian, 1), Equals, ErrOutOfRange)
+}
+
+func (s *TestSuite) TestPutAtOutsideOfTheContentFails(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("abc")
+
+  c.Assert(item.PutUint16At(2, binary.BigEndian, 1), Equals, ErrOutOfRange)
+  c.Assert(item.PutUint32At(-1, binary.BigEndian, 1), Equals, ErrOutOfRange)
+  c.Assert(item.PutUint64At(0, binary.BigEndian, 1), Equals, ErrOutOfRange)
+}
+
+func (s *TestSuite) TestPutUvarintPadsToTheReservedWidth(c *C) {
+  item := newItem(20, nil)
+  r, _ := item.Reserve(3)
+  item.WriteString("!")
+
+  for _, v := range []uint64{0, 1, 300, 1<<21 - 1} {
+    c.Assert(r.PutUvarint(v), IsNil)
+    actual, n := binary.Uvarint(item.Bytes())
+    c.Assert(actual, Equals, v, Commentf("Expecting %d, got %d", v, actual))
+    c.Assert(n, Equals, 3, Commentf("Expecting 3 bytes to be decoded, got %d", n))
+  }
+  c.Assert(r.PutUvarint(1<<21), NotNil, Commentf("Expecting an error for a value larger than the width"))
+  c.Assert(item.PutUvarintAt(0, 11, 1), NotNil, Commentf("Expecting an error for a width larger than a varint"))
+}
+
+func (s *TestSuite) TestWritesAndReadsFixedWidthIntegers(c *C) {
+  for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+    item := newItem(64, nil)
+    item.WriteUint16(order, 9000)
+    item.WriteUint32(order, 1<<31)
+    item.WriteUint64(order, 1<<63)
+    item.WriteInt16(order, -9000)
+    item.WriteInt32(order, -1<<31)
+    item.WriteInt64(order, -1<<63)
+    c.Assert(item.Len(), Equals, 28, Commentf("Expecting a length of 28, got %d", item.Len()))
+
+    u16, _ := item.ReadUint16(order)
+    c.Assert(u16, Equals, uint16(9000))
+    u32, _ := item.ReadUint32(order)
+    c.Assert(u32, Equals, uint32(1<<31))
+    u64, _ := item.ReadUint64(order)
+    c.Assert(u64, Equals, uint64(1<<63))
+    i16, _ := item.ReadInt16(order)
+    c.Assert(i16, Equals, int16(-9000))
+    i32, _ := item.ReadInt32(order)
+    c.Assert(i32, Equals, int32(-1<<31))
+    i64, _ := item.ReadInt64(order)
+    c.Assert(i64, Equals, int64(-1<<63))
+  }
+}
+
+func (s *TestSuite) TestWritesBigEndianIntegers(c *C) {
+  item := newItem(4, nil)
+  item.WriteUint32(binary.BigEndian, 0x01020304)
+
+  c.Assert(item.Bytes(), DeepEquals, []byte{1, 2, 3, 4})
+}
+
+func (s *TestSuite) TestWritesAndReadsFloats(c *C) {
+  item := newItem(12, nil)
+  item.WriteFloat32(binary.LittleEndian, 9000.5)
+  item.WriteFloat64(binary.BigEndian, -0.25)
+
+  f32, _ := item.ReadFloat32(binary.LittleEndian)
+  c.Assert(f32, Equals, float32(9000.5))
+  f64, _ := item.ReadFloat64(binary.BigEndian)
+  c.Assert(f64, Equals, -0.25)
+}
+
+func (s *TestSuite) TestWritesAndReadsVarints(c *C) {
+  item := newItem(64, nil)
+  item.WriteUvarint(300)
+  item.WriteVarint(-9000)
+  item.WriteUvarint(1<<64 - 1)
+  c.Assert(item.Bytes()[:2], DeepEquals, []byte{0xac, 0x02})
+
+  u, _ := item.ReadUvarint()
+  c.Assert(u, Equals, uint64(300))
+  i, _ := item.ReadVarint()
+  c.Assert(i, Equals, int64(-9000))
+  u, _ = item.ReadUvarint()
+  c.Assert(u, Equals, uint64(1<<64-1))
+
+  _, err := item.ReadUvarint()
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+}
+
+func (s *TestSuite) TestBinaryWritesReportOverflows(c *C) {
+  item := newItem(3, nil)
+  item.WriteByte('!')
+
+  c.Assert(item.WriteUint32(binary.BigEndian, 1), Equals, io.ErrShortWrite)
+  c.Assert(item.WriteUvarint(1<<21), Equals, io.ErrShortWrite)
+  c.Assert(item.Len(), Equals, 1, Commentf("Expecting nothing to be written, got a length of %d", item.Len()))
+  c.Assert(item.WriteUint16(binary.BigEndian, 1), IsNil)
+}
+
+func (s *TestSuite) TestBinaryReadsReportUnderflows(c *C) {
+  item := newItem(10, nil)
+  item.Write([]byte{1, 2, 0x80})
+
+  _, err := item.ReadUint32(binary.BigEndian)
+  c.Assert(err, Equals, io.ErrUnexpectedEOF, Commentf("Expecting io.ErrUnexpectedEOF, got %v", err))
+  v, err := item.ReadUint16(binary.BigEndian)
+  c.Assert(v, Equals, uint16(0x0102), Commentf("Expecting the failed read not to move the cursor"))
+
+  _, err = item.ReadUvarint()
+  c.Assert(err, Equals, io.ErrUnexpectedEOF, Commentf("Expecting io.ErrUnexpectedEOF, got %v", err))
+  item.ReadByte()
+  _, err = item.ReadUint16(binary.BigEndian)
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+}
+
+func (s *TestSuite) TestReadVarintReportsOverflows(c *C) {
+  item := newItem(20, nil)
+  item.Write([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+
+  _, err := item.ReadUvarint()
+  c.Assert(err, NotNil, Commentf("Expecting an overflow error"))
+}
@@ -0,0 +1,85 @@
+package bytepool
+
+import (
+  "io"
+)
+
+// The behavior shared by every item handed out by a pool
+// (Item, JsonItem, or any fake a caller wants to inject)
+type Buffer interface {
+  io.Reader
+  io.Writer
+  io.ReaderFrom
+  io.WriterTo
+  io.Closer
+  io.ByteWriter
+  io.StringWriter
+  Available() int
+  Bytes() []byte
+  Raw() []byte
+  String() string
+  Len() int
+  TrimLastIf(b byte) bool
+  Position(position int) bool
+  Full() bool
+  Drained() bool
+}
+
+// The behavior shared by every pool. Pool and JsonPool hand out their
+// concrete item type, AsBufferPool adapts them to this interface
+type BufferPool interface {
+  Checkout() Buffer
+  Len() int
+  Misses() int
+  Stats() Stats
+}
+
+// a pool handing out a concrete item type
+type pool[T Buffer] interface {
+  Checkout() T
+  Len() int
+  Misses() int
+  Stats() Stats
+}
+
+// adapts a pool[T] to BufferPool
+type bufferPool[T Buffer] struct {
+  pool[T]
+}
+
+func (p bufferPool[T]) Checkout() Buffer {
+  return p.pool.Checkout()
+}
+
+// The pool as a BufferPool
+func (pool *Pool) AsBufferPool() BufferPool {
+  return bufferPool[*Item]{pool}
+}
+
+// The pool as a BufferPool
+func (pool *JsonPool) AsBufferPool() BufferPool {
+  return bufferPool[*JsonItem]{pool}
+}
+
+// A snapshot of a pool's counters
+//    capacity: size of each item
+//    count: no of items the pool was created with
+//    available: no of items currently inside the pool
+//    misses: no of checkouts which had to create an item on the fly
+//    rejected: no of slices which were given back but didn't belong to the pool
+//    losses: no of items which were detached from the pool (and replaced)
+type Stats struct {
+  Capacity  int
+  Count     int
+  Available int
+  Misses    int
+  Rejected  int
+  Losses    int
+}
+
+var (
+  _ Buffer          = (*Item)(nil)
+  _ Buffer          = (*JsonItem)(nil)
+  _ pool[*Item]     = (*Pool)(nil)
+  _ pool[*JsonItem] = (*JsonPool)(nil)
+)
@@ -0,0 +1,67 @@
+package bytepool
+
+import (
+  . "gopkg.in/check.v1"
+)
+
+func useBufferPool(pool BufferPool, s string) string {
+  buffer := pool.Checkout()
+  defer buffer.Close()
+  buffer.WriteString(s)
+  return buffer.String()
+}
+
+func (s *TestSuite) TestBufferPoolWorksWithAPool(c *C) {
+  p := New(1, 20)
+  actual := useBufferPool(p.AsBufferPool(), "over 9000")
+
+  c.Assert(actual, Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", actual))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestBufferPoolWorksWithAJsonPool(c *C) {
+  p := NewJson(1, 20)
+  actual := useBufferPool(p.AsBufferPool(), `over "9000"`)
+
+  c.Assert(actual, Equals, `"over \"9000\""`, Commentf("Expecting %q, got %q", `"over \"9000\""`, actual))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+  c.Assert(p.AsBufferPool().Stats(), Equals, p.Stats())
+}
+
+// a fake item, only implementing what the test needs
+type fakeBuffer struct {
+  Buffer
+  written string
+  closed  bool
+}
+
+func (b *fakeBuffer) WriteString(s string) (int, error) {
+  b.written += s
+  return len(s), nil
+}
+
+func (b *fakeBuffer) String() string {
+  return b.written
+}
+
+func (b *fakeBuffer) Close() error {
+  b.closed = true
+  return nil
+}
+
+type fakePool struct {
+  BufferPool
+  buffer *fakeBuffer
+}
+
+func (p *fakePool) Checkout() Buffer {
+  return p.buffer
+}
+
+func (s *TestSuite) TestBufferPoolCanBeFaked(c *C) {
+  p := &fakePool{buffer: new(fakeBuffer)}
+  actual := useBufferPool(p, "over 9000")
+
+  c.Assert(actual, Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", actual))
+  c.Assert(p.buffer.closed, Equals, true, Commentf("Expecting the buffer to be closed"))
+}
@@ -0,0 +1,398 @@
+package bytepool
+
+import (
+  "bufio"
+  "bytes"
+  "io"
+)
+
+// no of consecutive empty reads after which a reader is considered stuck
+const maxConsecutiveEmptyReads = 100
+
+// A buffered reader which behaves like bufio.Reader but whose buffer
+// is an item checked out from a pool. Close returns the item
+//    item: the item providing the buffer
+//    rd: the underlying reader
+//    r, w: read and write positions within the buffer
+//    err: the last error returned by rd
+//    lastByte: the last byte read, for UnreadByte (-1 means invalid)
+type Reader struct {
+  item     *Item
+  rd       io.Reader
+  r, w     int
+  err      error
+  lastByte int
+}
+
+func NewReader(pool *Pool, rd io.Reader) *Reader {
+  return &Reader{
+    item:     pool.Checkout(),
+    rd:       rd,
+    lastByte: -1,
+  }
+}
+
+// size of the underlying buffer
+func (b *Reader) Size() int {
+  return len(b.item.bytes)
+}
+
+// no of bytes that can be read from the current buffer
+func (b *Reader) Buffered() int {
+  return b.w - b.r
+}
+
+// read a new chunk into the buffer
+func (b *Reader) fill() {
+  buf := b.item.bytes
+  if b.r > 0 {
+    copy(buf, buf[b.r:b.w])
+    b.w -= b.r
+    b.r = 0
+  }
+  for i := maxConsecutiveEmptyReads; i > 0; i-- {
+    n, err := b.rd.Read(buf[b.w:])
+    b.w += n
+    if err != nil {
+      b.err = err
+      return
+    }
+    if n > 0 {
+      return
+    }
+  }
+  b.err = io.ErrNoProgress
+}
+
+func (b *Reader) readErr() error {
+  err := b.err
+  b.err = nil
+  return err
+}
+
+// return the next n bytes without advancing the reader
+// the bytes stop being valid at the next read call
+func (b *Reader) Peek(n int) ([]byte, error) {
+  if n < 0 {
+    return nil, bufio.ErrNegativeCount
+  }
+  b.lastByte = -1
+  buf := b.item.bytes
+  for b.w-b.r < n && b.w-b.r < len(buf) && b.err == nil {
+    b.fill()
+  }
+  if n > len(buf) {
+    return buf[b.r:b.w], bufio.ErrBufferFull
+  }
+  var err error
+  if available := b.w - b.r; available < n {
+    n = available
+    err = b.readErr()
+    if err == nil {
+      err = bufio.ErrBufferFull
+    }
+  }
+  return buf[b.r : b.r+n], err
+}
+
+// skip the next n bytes, returning the number of bytes discarded
+func (b *Reader) Discard(n int) (int, error) {
+  if n < 0 {
+    return 0, bufio.ErrNegativeCount
+  }
+  if n == 0 {
+    return 0, nil
+  }
+  b.lastByte = -1
+  remain := n
+  for {
+    skip := b.Buffered()
+    if skip == 0 {
+      b.fill()
+      skip = b.Buffered()
+    }
+    if skip > remain {
+      skip = remain
+    }
+    b.r += skip
+    remain -= skip
+    if remain == 0 {
+      return n, nil
+    }
+    if b.err != nil {
+      return n - remain, b.readErr()
+    }
+  }
+}
+
+// read data into p, at most one Read is issued on the underlying reader
+func (b *Reader) Read(p []byte) (int, error) {
+  if len(p) == 0 {
+    if b.Buffered() > 0 {
+      return 0, nil
+    }
+    return 0, b.readErr()
+  }
+  buf := b.item.bytes
+  if b.r == b.w {
+    if b.err != nil {
+      return 0, b.readErr()
+    }
+    if len(p) >= len(buf) {
+      // large read, skip the buffer
+      n, err := b.rd.Read(p)
+      b.err = err
+      if n > 0 {
+        b.lastByte = int(p[n-1])
+      }
+      return n, b.readErr()
+    }
+    b.r, b.w = 0, 0
+    n, err := b.rd.Read(buf)
+    b.err = err
+    if n == 0 {
+      return 0, b.readErr()
+    }
+    b.w += n
+  }
+  n := copy(p, buf[b.r:b.w])
+  b.r += n
+  b.lastByte = int(buf[b.r-1])
+  return n, nil
+}
+
+// read a single byte
+func (b *Reader) ReadByte() (byte, error) {
+  for b.r == b.w {
+    if b.err != nil {
+      return 0, b.readErr()
+    }
+    b.fill()
+  }
+  c := b.item.bytes[b.r]
+  b.r++
+  b.lastByte = int(c)
+  return c, nil
+}
+
+// unread the last byte, only the most recently read byte can be unread
+func (b *Reader) UnreadByte() error {
+  if b.lastByte < 0 || b.r == 0 && b.w > 0 {
+    return bufio.ErrInvalidUnreadByte
+  }
+  if b.r > 0 {
+    b.r--
+  } else {
+    b.w = 1
+  }
+  b.item.bytes[b.r] = byte(b.lastByte)
+  b.lastByte = -1
+  return nil
+}
+
+// read until the first occurrence of delim, returning a slice of the buffer
+// the bytes stop being valid at the next read call
+func (b *Reader) ReadSlice(delim byte) (line []byte, err error) {
+  buf := b.item.bytes
+  s := 0
+  for {
+    if i := bytes.IndexByte(buf[b.r+s:b.w], delim); i >= 0 {
+      i += s
+      line = buf[b.r : b.r+i+1]
+      b.r += i + 1
+      break
+    }
+    if b.err != nil {
+      line = buf[b.r:b.w]
+      b.r = b.w
+      err = b.readErr()
+      break
+    }
+    if b.Buffered() >= len(buf) {
+      b.r = b.w
+      line = buf
+      err = bufio.ErrBufferFull
+      break
+    }
+    s = b.w - b.r
+    b.fill()
+  }
+  if i := len(line) - 1; i >= 0 {
+    b.lastByte = int(line[i])
+  }
+  return line, err
+}
+
+// read a line, not including the end-of-line bytes, see bufio.Reader.ReadLine
+func (b *Reader) ReadLine() (line []byte, isPrefix bool, err error) {
+  line, err = b.ReadSlice('\n')
+  if err == bufio.ErrBufferFull {
+    if len(line) > 0 && line[len(line)-1] == '\r' {
+      b.r--
+      line = line[:len(line)-1]
+    }
+    return line, true, nil
+  }
+  if len(line) == 0 {
+    if err != nil {
+      line = nil
+    }
+    return line, false, err
+  }
+  err = nil
+  if line[len(line)-1] == '\n' {
+    drop := 1
+    if len(line) > 1 && line[len(line)-2] == '\r' {
+      drop = 2
+    }
+    line = line[:len(line)-drop]
+  }
+  return line, false, err
+}
+
+// read until the first occurrence of delim, returning a copy of the data
+func (b *Reader) ReadBytes(delim byte) ([]byte, error) {
+  var data []byte
+  for {
+    fragment, err := b.ReadSlice(delim)
+    data = append(data, fragment...)
+    if err != bufio.ErrBufferFull {
+      return data, err
+    }
+  }
+}
+
+// read until the first occurrence of delim, returning a string
+func (b *Reader) ReadString(delim byte) (string, error) {
+  data, err := b.ReadBytes(delim)
+  return string(data), err
+}
+
+// return the buffer to the pool
+// the reader must not be used afterwards
+func (b *Reader) Close() error {
+  return b.item.Close()
+}
+
+// A buffered writer which behaves like bufio.Writer but whose buffer
+// is an item checked out from a pool. Close flushes and returns the item
+//    item: the item providing the buffer
+//    wr: the underlying writer
+//    n: no of buffered bytes
+//    err: the last error returned by wr
+type Writer struct {
+  item *Item
+  wr   io.Writer
+  n    int
+  err  error
+}
+
+func NewWriter(pool *Pool, wr io.Writer) *Writer {
+  return &Writer{
+    item: pool.Checkout(),
+    wr:   wr,
+  }
+}
+
+// size of the underlying buffer
+func (b *Writer) Size() int {
+  return len(b.item.bytes)
+}
+
+// no of bytes unused in the buffer
+func (b *Writer) Available() int {
+  return len(b.item.bytes) - b.n
+}
+
+// no of bytes written into the buffer
+func (b *Writer) Buffered() int {
+  return b.n
+}
+
+// write any buffered data to the underlying writer
+func (b *Writer) Flush() error {
+  if b.err != nil {
+    return b.err
+  }
+  if b.n == 0 {
+    return nil
+  }
+  buf := b.item.bytes
+  n, err := b.wr.Write(buf[0:b.n])
+  if n < b.n && err == nil {
+    err = io.ErrShortWrite
+  }
+  if err != nil {
+    if n > 0 && n < b.n {
+      copy(buf[0:b.n-n], buf[n:b.n])
+    }
+    b.n -= n
+    b.err = err
+    return err
+  }
+  b.n = 0
+  return nil
+}
+
+// write p into the buffer, flushing as needed
+func (b *Writer) Write(p []byte) (int, error) {
+  written := 0
+  for len(p) > b.Available() && b.err == nil {
+    var n int
+    if b.Buffered() == 0 {
+      // large write, skip the buffer
+      n, b.err = b.wr.Write(p)
+    } else {
+      n = copy(b.item.bytes[b.n:], p)
+      b.n += n
+      b.Flush()
+    }
+    written += n
+    p = p[n:]
+  }
+  if b.err != nil {
+    return written, b.err
+  }
+  n := copy(b.item.bytes[b.n:], p)
+  b.n += n
+  return written + n, nil
+}
+
+// write a single byte into the buffer
+func (b *Writer) WriteByte(c byte) error {
+  if b.err != nil {
+    return b.err
+  }
+  if b.Available() <= 0 && b.Flush() != nil {
+    return b.err
+  }
+  b.item.bytes[b.n] = c
+  b.n++
+  return nil
+}
+
+// write s into the buffer, flushing as needed
+func (b *Writer) WriteString(s string) (int, error) {
+  written := 0
+  for len(s) > b.Available() && b.err == nil {
+    n := copy(b.item.bytes[b.n:], s)
+    b.n += n
+    written += n
+    s = s[n:]
+    b.Flush()
+  }
+  if b.err != nil {
+    return written, b.err
+  }
+  n := copy(b.item.bytes[b.n:], s)
+  b.n += n
+  return written + n, nil
+}
+
+// flush the buffered data and return the buffer to the pool
+// the writer must not be used afterwards
+func (b *Writer) Close() error {
+  err := b.Flush()
+  b.item.Close()
+  return err
+}
@@ -0,0 +1,176 @@
+package bytepool
+
+import (
+  "bufio"
+  "bytes"
+  . "gopkg.in/check.v1"
+  "io"
+  "strings"
+  "testing/iotest"
+)
+
+type emptyReader struct{}
+
+func (r emptyReader) Read(b []byte) (int, error) {
+  return 0, nil
+}
+
+func (s *TestSuite) TestReaderTakesItsBufferFromThePool(c *C) {
+  p := New(1, 16)
+  reader := NewReader(p, strings.NewReader("over 9000"))
+
+  c.Assert(reader.Size(), Equals, 16, Commentf("Expecting a size of 16, got %d", reader.Size()))
+  c.Assert(p.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.Len()))
+
+  reader.Close()
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestReaderPeeksWithoutAdvancing(c *C) {
+  reader := NewReader(New(1, 16), iotest.OneByteReader(strings.NewReader("over 9000")))
+  defer reader.Close()
+
+  peeked, err := reader.Peek(4)
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(string(peeked), Equals, "over", Commentf("Expecting %q, got %q", "over", peeked))
+
+  all, _ := io.ReadAll(reader)
+  c.Assert(string(all), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", all))
+}
+
+func (s *TestSuite) TestReaderPeekBeyondTheBufferIsAnError(c *C) {
+  reader := NewReader(New(1, 4), strings.NewReader("over 9000"))
+  defer reader.Close()
+
+  peeked, err := reader.Peek(6)
+  c.Assert(err, Equals, bufio.ErrBufferFull, Commentf("Expecting bufio.ErrBufferFull, got %v", err))
+  c.Assert(string(peeked), Equals, "over", Commentf("Expecting %q, got %q", "over", peeked))
+}
+
+func (s *TestSuite) TestReaderDiscards(c *C) {
+  reader := NewReader(New(1, 4), strings.NewReader("over 9000"))
+  defer reader.Close()
+
+  n, err := reader.Discard(5)
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, 5, Commentf("Expecting 5 discarded bytes, got %d", n))
+
+  rest, _ := reader.ReadString('!')
+  c.Assert(rest, Equals, "9000", Commentf("Expecting %q, got %q", "9000", rest))
+}
+
+func (s *TestSuite) TestReaderReadsAndUnreadsBytes(c *C) {
+  reader := NewReader(New(1, 4), strings.NewReader("ab"))
+  defer reader.Close()
+
+  b, _ := reader.ReadByte()
+  c.Assert(b, Equals, byte('a'), Commentf("Expecting 'a', got %q", b))
+  c.Assert(reader.UnreadByte(), IsNil)
+  c.Assert(reader.UnreadByte(), Equals, bufio.ErrInvalidUnreadByte)
+
+  b, _ = reader.ReadByte()
+  c.Assert(b, Equals, byte('a'), Commentf("Expecting 'a', got %q", b))
+  b, _ = reader.ReadByte()
+  c.Assert(b, Equals, byte('b'), Commentf("Expecting 'b', got %q", b))
+  _, err := reader.ReadByte()
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+}
+
+func (s *TestSuite) TestReaderReadsSlices(c *C) {
+  reader := NewReader(New(1, 8), strings.NewReader("a,bc,def"))
+  defer reader.Close()
+
+  for _, expected := range []string{"a,", "bc,", "def"} {
+    line, _ := reader.ReadSlice(',')
+    c.Assert(string(line), Equals, expected, Commentf("Expecting %q, got %q", expected, line))
+  }
+  _, err := reader.ReadSlice(',')
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+}
+
+func (s *TestSuite) TestReaderReadSliceFailsWhenTheBufferIsFull(c *C) {
+  reader := NewReader(New(1, 4), strings.NewReader("over,9000"))
+  defer reader.Close()
+
+  line, err := reader.ReadSlice(',')
+  c.Assert(err, Equals, bufio.ErrBufferFull, Commentf("Expecting bufio.ErrBufferFull, got %v", err))
+  c.Assert(string(line), Equals, "over", Commentf("Expecting %q, got %q", "over", line))
+}
+
+func (s *TestSuite) TestReaderReadsLines(c *C) {
+  reader := NewReader(New(1, 8), strings.NewReader("hello\r\nover 9000\nend"))
+  defer reader.Close()
+
+  expected := []struct {
+    line     string
+    isPrefix bool
+  }{{"hello", false}, {"over 900", true}, {"0", false}, {"end", false}}
+  for _, e := range expected {
+    line, isPrefix, err := reader.ReadLine()
+    c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+    c.Assert(string(line), Equals, e.line, Commentf("Expecting %q, got %q", e.line, line))
+    c.Assert(isPrefix, Equals, e.isPrefix, Commentf("Expecting isPrefix %v for %q", e.isPrefix, e.line))
+  }
+  _, _, err := reader.ReadLine()
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+}
+
+func (s *TestSuite) TestReaderReadsBytesLargerThanTheBuffer(c *C) {
+  reader := NewReader(New(1, 4), strings.NewReader("over 9000\nend"))
+  defer reader.Close()
+
+  line, err := reader.ReadBytes('\n')
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(string(line), Equals, "over 9000\n", Commentf("Expecting %q, got %q", "over 9000\n", line))
+}
+
+func (s *TestSuite) TestReaderGivesUpOnAReaderThatMakesNoProgress(c *C) {
+  reader := NewReader(New(1, 4), emptyReader{})
+  defer reader.Close()
+
+  _, err := reader.ReadByte()
+  c.Assert(err, Equals, io.ErrNoProgress, Commentf("Expecting io.ErrNoProgress, got %v", err))
+}
+
+func (s *TestSuite) TestWriterBuffersUntilFlushed(c *C) {
+  p := New(1, 16)
+  dst := new(bytes.Buffer)
+  writer := NewWriter(p, dst)
+  writer.WriteString("over ")
+  writer.Write([]byte("90"))
+  writer.WriteByte('0')
+  writer.WriteByte('0')
+
+  c.Assert(dst.Len(), Equals, 0, Commentf("Expecting nothing to be written, got %q", dst.String()))
+  c.Assert(writer.Buffered(), Equals, 9, Commentf("Expecting 9 buffered bytes, got %d", writer.Buffered()))
+  c.Assert(writer.Available(), Equals, 7, Commentf("Expecting 7 available bytes, got %d", writer.Available()))
+
+  writer.Flush()
+  c.Assert(dst.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", dst.String()))
+  writer.Close()
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestWriterFlushesWhenFull(c *C) {
+  expected := "the spice must flow"
+  dst := new(bytes.Buffer)
+  writer := NewWriter(New(1, 4), dst)
+  writer.WriteString("the spice")
+  writer.Write([]byte(" must"))
+  writer.WriteString(" flow")
+
+  c.Assert(dst.Len() > 0, Equals, true, Commentf("Expecting some data to have been flushed"))
+  writer.Close()
+  c.Assert(dst.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, dst.String()))
+}
+
+func (s *TestSuite) TestWriterKeepsTheFlushError(c *C) {
+  p := New(1, 4)
+  writer := NewWriter(p, failingWriter{})
+  writer.WriteString("over")
+  _, err := writer.WriteString(" 9000")
+
+  c.Assert(err, ErrorMatches, "failed", Commentf("Expecting an error, got %v", err))
+  c.Assert(writer.Close(), ErrorMatches, "failed")
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
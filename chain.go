@@ -0,0 +1,189 @@
+package bytepool
+
+import (
+  "io"
+  "net"
+)
+
+// A sequence of items from the same pool which behaves like a single buffer,
+// for the odd payload which doesn't fit in one item. Items are checked out
+// as the chain fills up (up to a maximum) and are all returned to the pool on Close
+//    pool: the pool the links are checked out from
+//    items: the links, in order
+//    read: index of the link being read
+//    maxLinks: the maximum no of links
+type ItemChain struct {
+  pool     *Pool
+  items    []*Item
+  read     int
+  maxLinks int
+}
+
+// create a chain of at most maxLinks items checked out from pool, since
+// items are created on the fly when the pool is empty, this is what bounds
+// the memory a single chain can take
+func NewChain(pool *Pool, maxLinks int) *ItemChain {
+  return &ItemChain{pool: pool, maxLinks: maxLinks}
+}
+
+// the last link, checking out a new one when it's full
+// nil when it's full and the chain can't grow
+func (c *ItemChain) tail() *Item {
+  if l := len(c.items); l > 0 && !c.items[l-1].Full() {
+    return c.items[l-1]
+  }
+  if len(c.items) >= c.maxLinks {
+    return nil
+  }
+  item := c.pool.Checkout()
+  c.items = append(c.items, item)
+  return item
+}
+
+// write b at the end of the chain
+// returns io.ErrShortWrite when the chain is full
+func (c *ItemChain) Write(b []byte) (int, error) {
+  if c.pool.capacity == 0 && len(b) > 0 {
+    return 0, io.ErrShortWrite
+  }
+  written := 0
+  for len(b) > 0 {
+    item := c.tail()
+    if item == nil {
+      return written, io.ErrShortWrite
+    }
+    n, _ := item.Write(b)
+    written += n
+    b = b[n:]
+  }
+  return written, nil
+}
+
+// write s at the end of the chain
+// returns io.ErrShortWrite when the chain is full
+func (c *ItemChain) WriteString(s string) (int, error) {
+  if c.pool.capacity == 0 && len(s) > 0 {
+    return 0, io.ErrShortWrite
+  }
+  written := 0
+  for len(s) > 0 {
+    item := c.tail()
+    if item == nil {
+      return written, io.ErrShortWrite
+    }
+    n, _ := item.WriteString(s)
+    written += n
+    s = s[n:]
+  }
+  return written, nil
+}
+
+// read data from an io.Reader until io.EOF, adding links as needed
+// returns ErrBufferFull when the chain is full before io.EOF, see Item.ReadFrom
+func (c *ItemChain) ReadFrom(reader io.Reader) (int64, error) {
+  if c.pool.capacity == 0 {
+    return 0, ErrBufferFull
+  }
+  var read int64
+  for {
+    item := c.tail()
+    if item == nil {
+      return read, ErrBufferFull
+    }
+    n, err := item.fill(reader, cap(item.bytes))
+    read += int64(n)
+    if err == io.EOF {
+      return read, nil
+    }
+    if err != nil {
+      return read, err
+    }
+  }
+}
+
+// read data from the chain in to another byte-slice
+func (c *ItemChain) Read(p []byte) (int, error) {
+  n := 0
+  for n < len(p) && c.read < len(c.items) {
+    item := c.items[c.read]
+    if item.Drained() {
+      if c.read == len(c.items)-1 {
+        break
+      }
+      c.read++
+      continue
+    }
+    m := copy(p[n:], item.bytes[item.read:item.length])
+    item.read += m
+    n += m
+  }
+  if n == 0 && len(p) > 0 {
+    return 0, io.EOF
+  }
+  return n, nil
+}
+
+// write the unread content to w, with a single writev when w is a net.Conn
+func (c *ItemChain) WriteTo(w io.Writer) (int64, error) {
+  buffers := make(net.Buffers, 0, len(c.items)-c.read)
+  for _, item := range c.items[c.read:] {
+    if !item.Drained() {
+      buffers = append(buffers, item.bytes[item.read:item.length])
+    }
+  }
+  n, err := buffers.WriteTo(w)
+  c.skip(n)
+  return n, err
+}
+
+// advance the read cursor by n bytes
+func (c *ItemChain) skip(n int64) {
+  for n > 0 && c.read < len(c.items) {
+    item := c.items[c.read]
+    m := int64(item.length - item.read)
+    if m > n {
+      m = n
+    }
+    item.read += int(m)
+    n -= m
+    if item.Drained() && c.read < len(c.items)-1 {
+      c.read++
+    }
+  }
+}
+
+// the content of the chain, one slice per (non-empty) link
+// writing them to a net.Conn results in a single writev
+func (c *ItemChain) Buffers() net.Buffers {
+  buffers := make(net.Buffers, 0, len(c.items))
+  for _, item := range c.items {
+    if item.length > 0 {
+      buffers = append(buffers, item.Bytes())
+    }
+  }
+  return buffers
+}
+
+// total length of the content
+func (c *ItemChain) Len() int {
+  length := 0
+  for _, item := range c.items {
+    length += item.length
+  }
+  return length
+}
+
+// no of items in the chain
+func (c *ItemChain) Links() int {
+  return len(c.items)
+}
+
+// return every link to the pool, leaving the chain empty
+func (c *ItemChain) Close() error {
+  for _, item := range c.items {
+    item.Close()
+  }
+  c.items = nil
+  c.read = 0
+  return nil
+}
@@ -0,0 +1,100 @@
+package bytepool
+
+import (
+  "bytes"
+  . "gopkg.in/check.v1"
+  "io"
+  "strings"
+  "testing/iotest"
+)
+
+func (s *TestSuite) TestChainWritesAcrossLinks(c *C) {
+  expected := "the spice must flow"
+  p := New(5, 4)
+  chain := NewChain(p, 5)
+  chain.WriteString("the spice ")
+  chain.Write([]byte("must flow"))
+
+  c.Assert(chain.Len(), Equals, len(expected), Commentf("Expecting a length of %d, got %d", len(expected), chain.Len()))
+  c.Assert(chain.Links(), Equals, 5, Commentf("Expecting 5 links, got %d", chain.Links()))
+
+  all, _ := io.ReadAll(chain)
+  c.Assert(string(all), Equals, expected, Commentf("Expecting %q, got %q", expected, all))
+
+  chain.Close()
+  c.Assert(p.Len(), Equals, 5, Commentf("Expecting a pool length of 5, got %d", p.Len()))
+  c.Assert(chain.Len(), Equals, 0, Commentf("Expecting a length of 0, got %d", chain.Len()))
+}
+
+func (s *TestSuite) TestChainReadsFromAReader(c *C) {
+  expected := strings.Repeat("over 9000 ", 10)
+  chain := NewChain(New(2, 16), 7)
+  defer chain.Close()
+  n, err := chain.ReadFrom(iotest.HalfReader(strings.NewReader(expected)))
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(int(n), Equals, len(expected), Commentf("Expecting %d bytes read, got %d", len(expected), n))
+  c.Assert(string(bytes.Join(chain.Buffers(), nil)), Equals, expected)
+}
+
+func (s *TestSuite) TestChainReadsInSmallPieces(c *C) {
+  chain := NewChain(New(2, 4), 3)
+  defer chain.Close()
+  chain.WriteString("over 9000")
+
+  all, _ := io.ReadAll(iotest.OneByteReader(chain))
+  c.Assert(string(all), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", all))
+
+  chain.WriteString("!")
+  all, _ = io.ReadAll(chain)
+  c.Assert(string(all), Equals, "!", Commentf("Expecting %q, got %q", "!", all))
+}
+
+func (s *TestSuite) TestChainWritesToAWriter(c *C) {
+  chain := NewChain(New(2, 4), 3)
+  defer chain.Close()
+  chain.WriteString("over 9000")
+  chain.Read(make([]byte, 5))
+  buffer := new(bytes.Buffer)
+  n, err := chain.WriteTo(buffer)
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, int64(4), Commentf("Expecting 4 bytes written, got %d", n))
+  c.Assert(buffer.String(), Equals, "9000", Commentf("Expecting %q, got %q", "9000", buffer.String()))
+
+  n, _ = chain.WriteTo(buffer)
+  c.Assert(n, Equals, int64(0), Commentf("Expecting nothing left to write, got %d", n))
+}
+
+func (s *TestSuite) TestChainStopsAtItsMaximumNoOfLinks(c *C) {
+  p := New(1, 4)
+  chain := NewChain(p, 2)
+  n, err := chain.WriteString("over 9000")
+
+  c.Assert(n, Equals, 8, Commentf("Expecting 8 bytes written, got %d", n))
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  c.Assert(chain.Links(), Equals, 2, Commentf("Expecting 2 links, got %d", chain.Links()))
+  chain.Close()
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestChainReadFromStopsAtItsMaximumNoOfLinks(c *C) {
+  chain := NewChain(New(1, 4), 2)
+  defer chain.Close()
+  n, err := chain.ReadFrom(strings.NewReader("over 9000"))
+
+  c.Assert(err, Equals, ErrBufferFull, Commentf("Expecting ErrBufferFull, got %v", err))
+  c.Assert(n, Equals, int64(8), Commentf("Expecting 8 bytes read, got %d", n))
+  c.Assert(chain.Links(), Equals, 2, Commentf("Expecting 2 links, got %d", chain.Links()))
+}
+
+func (s *TestSuite) TestChainReadFromDoesNotReadOnceFull(c *C) {
+  chain := NewChain(New(1, 4), 2)
+  defer chain.Close()
+  reader := strings.NewReader("over9000!")
+  n, err := chain.ReadFrom(reader)
+
+  c.Assert(err, Equals, ErrBufferFull, Commentf("Expecting ErrBufferFull, got %v", err))
+  c.Assert(n, Equals, int64(8), Commentf("Expecting 8 bytes read, got %d", n))
+  c.Assert(reader.Len(), Equals, 1, Commentf("Expecting 1 byte left in the reader, got %d", reader.Len()))
+}
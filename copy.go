@@ -0,0 +1,33 @@
+package bytepool
+
+import (
+  "io"
+)
+
+// Copy from src to dst (like io.Copy) using a buffer borrowed from the pool
+// when src is an io.WriterTo or dst an io.ReaderFrom, no buffer is needed
+// and none is checked out. The buffer is always returned to the pool
+func Copy(dst io.Writer, src io.Reader, pool *Pool) (int64, error) {
+  if wt, ok := src.(io.WriterTo); ok {
+    return wt.WriteTo(dst)
+  }
+  if rf, ok := dst.(io.ReaderFrom); ok {
+    return rf.ReadFrom(src)
+  }
+  item := pool.Checkout()
+  defer item.Close()
+  return io.CopyBuffer(dst, src, item.Raw())
+}
+
+// Copy n bytes (or until an error) from src to dst (like io.CopyN)
+// using a buffer borrowed from the pool
+func CopyN(dst io.Writer, src io.Reader, n int64, pool *Pool) (int64, error) {
+  written, err := Copy(dst, io.LimitReader(src, n), pool)
+  if written == n {
+    return n, nil
+  }
+  if written < n && err == nil {
+    err = io.EOF
+  }
+  return written, err
+}
@@ -0,0 +1,87 @@
+package bytepool
+
+import (
+  "bytes"
+  "errors"
+  . "gopkg.in/check.v1"
+  "io"
+  "strings"
+)
+
+// hides any fast path (io.WriterTo / io.ReaderFrom) of the wrapped type
+type plainReader struct{ io.Reader }
+type plainWriter struct{ io.Writer }
+
+type failingWriter struct{}
+
+func (w failingWriter) Write(b []byte) (int, error) {
+  return 0, errors.New("failed")
+}
+
+type panickingWriter struct{}
+
+func (w panickingWriter) Write(b []byte) (int, error) {
+  panic("panicking")
+}
+
+func (s *TestSuite) TestCopyUsesAPooledBuffer(c *C) {
+  expected := strings.Repeat("it's over 9000!", 10)
+  p := New(1, 4)
+  dst := new(bytes.Buffer)
+  n, err := Copy(plainWriter{dst}, plainReader{strings.NewReader(expected)}, p)
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(int(n), Equals, len(expected), Commentf("Expecting %d bytes, got %d", len(expected), n))
+  c.Assert(dst.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, dst.String()))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+  c.Assert(p.Misses(), Equals, 0, Commentf("Expecting a miss count of 0, got %d", p.Misses()))
+}
+
+func (s *TestSuite) TestCopyDoesNotCheckoutABufferForFastPaths(c *C) {
+  p := New(1, 4)
+  p.Checkout()
+  dst := new(bytes.Buffer)
+  Copy(dst, plainReader{strings.NewReader("over 9000")}, p)
+  Copy(plainWriter{dst}, strings.NewReader("over 9000"), p)
+
+  c.Assert(dst.String(), Equals, "over 9000over 9000", Commentf("Expecting %q, got %q", "over 9000over 9000", dst.String()))
+  c.Assert(p.Misses(), Equals, 0, Commentf("Expecting a miss count of 0, got %d", p.Misses()))
+}
+
+func (s *TestSuite) TestCopyReturnsTheBufferOnError(c *C) {
+  p := New(1, 4)
+  _, err := Copy(failingWriter{}, plainReader{strings.NewReader("over 9000")}, p)
+
+  c.Assert(err, ErrorMatches, "failed", Commentf("Expecting an error, got %v", err))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestCopyReturnsTheBufferOnPanic(c *C) {
+  p := New(1, 4)
+  func() {
+    defer func() { recover() }()
+    Copy(panickingWriter{}, plainReader{strings.NewReader("over 9000")}, p)
+  }()
+
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestCopyNCopiesNBytes(c *C) {
+  p := New(1, 4)
+  dst := new(bytes.Buffer)
+  n, err := CopyN(plainWriter{dst}, plainReader{strings.NewReader("over 9000")}, 6, p)
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, int64(6), Commentf("Expecting 6 bytes, got %d", n))
+  c.Assert(dst.String(), Equals, "over 9", Commentf("Expecting %q, got %q", "over 9", dst.String()))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestCopyNReturnsEOFWhenSourceIsShort(c *C) {
+  p := New(1, 4)
+  dst := new(bytes.Buffer)
+  n, err := CopyN(dst, strings.NewReader("over"), 6, p)
+
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+  c.Assert(n, Equals, int64(4), Commentf("Expecting 4 bytes, got %d", n))
+}
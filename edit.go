@@ -0,0 +1,111 @@
+package bytepool
+
+import (
+  "bytes"
+  "unsafe"
+)
+
+// The editing methods shift the content within the item's slice. When the
+// result doesn't fit, nothing is changed and ErrBufferFull is returned.
+// Arguments may point into the item's own slice (e.g. item.Bytes()[1:3]),
+// such arguments are copied before anything is shifted
+
+// insert b at position at of the content, the read cursor keeps
+// pointing to the same byte
+func (item *Item) Insert(at int, b []byte) error {
+  if at < 0 || at > item.length {
+    return ErrOutOfRange
+  }
+  if len(b) > item.Available() {
+    return ErrBufferFull
+  }
+  b = item.own(b)
+  item.rehash(at)
+  copy(item.bytes[at+len(b):], item.bytes[at:item.length])
+  copy(item.bytes[at:], b)
+  item.length += len(b)
+  if item.read > at {
+    item.read += len(b)
+  }
+  item.lastRead = 0
+  return nil
+}
+
+// insert b at the start of the content
+func (item *Item) Prepend(b []byte) error {
+  return item.Insert(0, b)
+}
+
+// remove content[i:j], a read cursor within the removed part
+// is moved to i
+func (item *Item) Delete(i, j int) error {
+  if i < 0 || j < i || j > item.length {
+    return ErrOutOfRange
+  }
+  item.rehash(i)
+  copy(item.bytes[i:], item.bytes[j:item.length])
+  item.length -= j - i
+  if item.read >= j {
+    item.read -= j - i
+  } else if item.read > i {
+    item.read = i
+  }
+  item.lastRead = 0
+  return nil
+}
+
+// replace every non-overlapping occurrence of old by new, returning the no of
+// replacements. old can't be empty. The read cursor is moved to the start
+func (item *Item) ReplaceAll(old, new []byte) (int, error) {
+  if len(old) == 0 {
+    return 0, nil
+  }
+  count := bytes.Count(item.bytes[:item.length], old)
+  if count == 0 {
+    return 0, nil
+  }
+  length := item.length + count*(len(new)-len(old))
+  if length > cap(item.bytes) {
+    return 0, ErrBufferFull
+  }
+  old, new = item.own(old), item.own(new)
+  item.rehash(0)
+  source := item.bytes[:item.length]
+  if len(new) > len(old) {
+    // move the content to the end of the slice so that the replacements,
+    // written from the start, never catch up with the unprocessed content
+    start := cap(item.bytes) - item.length
+    copy(item.bytes[start:], source)
+    source = item.bytes[start:]
+  }
+  w := 0
+  for {
+    i := bytes.Index(source, old)
+    if i < 0 {
+      break
+    }
+    w += copy(item.bytes[w:], source[:i])
+    w += copy(item.bytes[w:], new)
+    source = source[i+len(old):]
+  }
+  copy(item.bytes[w:], source)
+  item.length = length
+  item.read = 0
+  item.lastRead = 0
+  return count, nil
+}
+
+// b, or a copy of it when it overlaps the item's slice, which is about to
+// be shifted
+func (item *Item) own(b []byte) []byte {
+  if len(b) == 0 || cap(item.bytes) == 0 {
+    return b
+  }
+  start := uintptr(unsafe.Pointer(unsafe.SliceData(item.bytes)))
+  end := start + uintptr(cap(item.bytes))
+  p := uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+  if p+uintptr(len(b)) <= start || p >= end {
+    return b
+  }
+  return append([]byte(nil), b...)
+}
@@ -0,0 +1,110 @@
+package bytepool
+
+import (
+  "bytes"
+  . "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestInsertShiftsTheContent(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  item.Next(5)
+
+  c.Assert(item.Insert(4, []byte(" is")), IsNil)
+  c.Assert(item.String(), Equals, "over is 9000", Commentf("Expecting %q, got %q", "over is 9000", item.String()))
+  rest := string(item.Next(10))
+  c.Assert(rest, Equals, "9000", Commentf("Expecting the read cursor to have moved, got %q", rest))
+}
+
+func (s *TestSuite) TestPrependAddsAHeader(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("9000")
+
+  c.Assert(item.Prepend([]byte("over ")), IsNil)
+  c.Assert(item.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", item.String()))
+}
+
+func (s *TestSuite) TestInsertFailsWhenItDoesNotFit(c *C) {
+  item := newItem(6, nil)
+  item.WriteString("over")
+
+  c.Assert(item.Insert(0, []byte("abc")), Equals, ErrBufferFull)
+  c.Assert(item.Insert(5, []byte("a")), Equals, ErrOutOfRange)
+  c.Assert(item.String(), Equals, "over", Commentf("Expecting %q, got %q", "over", item.String()))
+}
+
+func (s *TestSuite) TestDeleteRemovesARange(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over is 9000")
+  item.Next(6)
+
+  c.Assert(item.Delete(4, 7), IsNil)
+  c.Assert(item.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", item.String()))
+  rest := string(item.Next(10))
+  c.Assert(rest, Equals, " 9000", Commentf("Expecting the read cursor to be moved to the deletion, got %q", rest))
+
+  c.Assert(item.Delete(0, 5), IsNil)
+  c.Assert(item.String(), Equals, "9000", Commentf("Expecting %q, got %q", "9000", item.String()))
+  c.Assert(item.Delete(2, 5), Equals, ErrOutOfRange)
+}
+
+func (s *TestSuite) TestReplaceAllWithAShorterValue(c *C) {
+  item := newItem(30, nil)
+  item.WriteString("the spice must flow, spice!")
+  n, err := item.ReplaceAll([]byte("spice"), []byte("ale"))
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, 2, Commentf("Expecting 2 replacements, got %d", n))
+  c.Assert(item.String(), Equals, "the ale must flow, ale!", Commentf("Expecting %q, got %q", "the ale must flow, ale!", item.String()))
+}
+
+func (s *TestSuite) TestReplaceAllWithALongerValue(c *C) {
+  expected := "a-x-b-x-c-x-"
+  item := newItem(len(expected), nil)
+  item.WriteString("a,b,c,")
+  n, err := item.ReplaceAll([]byte(","), []byte("-x-"))
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, 3, Commentf("Expecting 3 replacements, got %d", n))
+  c.Assert(item.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, item.String()))
+}
+
+func (s *TestSuite) TestReplaceAllMatchesLikeBytesReplaceAll(c *C) {
+  for _, input := range []string{"aaaa", "aaa", "baaab", "abaaba"} {
+    item := newItem(20, nil)
+    item.WriteString(input)
+    item.ReplaceAll([]byte("aa"), []byte("xyz"))
+    expected := string(bytes.ReplaceAll([]byte(input), []byte("aa"), []byte("xyz")))
+    c.Assert(item.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, item.String()))
+  }
+}
+
+func (s *TestSuite) TestReplaceAllFailsWhenItDoesNotFit(c *C) {
+  item := newItem(8, nil)
+  item.WriteString("a,b,c")
+  _, err := item.ReplaceAll([]byte(","), []byte("---"))
+
+  c.Assert(err, Equals, ErrBufferFull, Commentf("Expecting ErrBufferFull, got %v", err))
+  c.Assert(item.String(), Equals, "a,b,c", Commentf("Expecting %q, got %q", "a,b,c", item.String()))
+}
+
+func (s *TestSuite) TestEditsWithArgumentsFromTheItemItself(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("abc")
+  item.Insert(0, item.Bytes()[1:3])
+  c.Assert(item.String(), Equals, "bcabc", Commentf("Expecting %q, got %q", "bcabc", item.String()))
+
+  item.Prepend(item.Bytes())
+  c.Assert(item.String(), Equals, "bcabcbcabc", Commentf("Expecting %q, got %q", "bcabcbcabc", item.String()))
+
+  item.Reset()
+  item.WriteString("abab")
+  n, err := item.ReplaceAll(item.Bytes()[0:1], item.Bytes()[0:2])
+  c.Assert(err, IsNil)
+  c.Assert(n, Equals, 2, Commentf("Expecting 2 replacements, got %d", n))
+  c.Assert(item.String(), Equals, "abbabb", Commentf("Expecting %q, got %q", "abbabb", item.String()))
+
+  n, _ = item.ReplaceAll(item.Bytes()[1:3], item.Bytes()[0:1])
+  c.Assert(n, Equals, 2, Commentf("Expecting 2 replacements, got %d", n))
+  c.Assert(item.String(), Equals, "aaaa", Commentf("Expecting %q, got %q", "aaaa", item.String()))
+}
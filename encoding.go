@@ -0,0 +1,158 @@
+package bytepool
+
+import (
+  "encoding/base64"
+  "encoding/hex"
+  "io"
+  "net/url"
+)
+
+// The encoding methods encode (or decode) directly into the item. When the
+// result doesn't fit, or the input can't be decoded, nothing is written
+
+const upperhex = "0123456789ABCDEF"
+
+// write b encoded with enc, e.g. base64.URLEncoding
+func (item *Item) WriteBase64(b []byte, enc *base64.Encoding) (int, error) {
+  dst, err := item.grab(enc.EncodedLen(len(b)))
+  if err != nil {
+    return 0, err
+  }
+  enc.Encode(dst, b)
+  return len(dst), nil
+}
+
+// write src decoded with enc. Returns io.ErrShortWrite unless there's room
+// for enc.DecodedLen(len(src)) bytes, which can be slightly more than needed
+func (item *Item) WriteBase64Decoded(src []byte, enc *base64.Encoding) (int, error) {
+  max := enc.DecodedLen(len(src))
+  if max > item.Available() {
+    return 0, io.ErrShortWrite
+  }
+  n, err := enc.Decode(item.bytes[item.length:item.length+max], src)
+  if err != nil {
+    return 0, err
+  }
+  item.length += n
+  return n, nil
+}
+
+// write b encoded in lowercase hexadecimal
+func (item *Item) WriteHex(b []byte) (int, error) {
+  dst, err := item.grab(hex.EncodedLen(len(b)))
+  if err != nil {
+    return 0, err
+  }
+  return hex.Encode(dst, b), nil
+}
+
+// write src decoded from hexadecimal
+func (item *Item) WriteHexDecoded(src []byte) (int, error) {
+  n := hex.DecodedLen(len(src))
+  if n > item.Available() {
+    return 0, io.ErrShortWrite
+  }
+  n, err := hex.Decode(item.bytes[item.length:item.length+n], src)
+  if err != nil {
+    return 0, err
+  }
+  item.length += n
+  return n, nil
+}
+
+// write s escaped so that it can be placed in a URL query, see url.QueryEscape
+func (item *Item) WriteURLEscaped(s string) (int, error) {
+  n := 0
+  for i := 0; i < len(s); i++ {
+    if c := s[i]; c == ' ' || !shouldEscape(c) {
+      n++
+    } else {
+      n += 3
+    }
+  }
+  dst, err := item.grab(n)
+  if err != nil {
+    return 0, err
+  }
+  j := 0
+  for i := 0; i < len(s); i++ {
+    switch c := s[i]; {
+    case c == ' ':
+      dst[j] = '+'
+      j++
+    case shouldEscape(c):
+      dst[j] = '%'
+      dst[j+1] = upperhex[c>>4]
+      dst[j+2] = upperhex[c&15]
+      j += 3
+    default:
+      dst[j] = c
+      j++
+    }
+  }
+  return n, nil
+}
+
+// write s with its URL query escaping undone, see url.QueryUnescape
+// returns an url.EscapeError when s is malformed
+func (item *Item) WriteURLUnescaped(s string) (int, error) {
+  n := 0
+  for i := 0; i < len(s); i++ {
+    if s[i] == '%' {
+      if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+        s = s[i:]
+        if len(s) > 3 {
+          s = s[:3]
+        }
+        return 0, url.EscapeError(s)
+      }
+      i += 2
+    }
+    n++
+  }
+  dst, err := item.grab(n)
+  if err != nil {
+    return 0, err
+  }
+  j := 0
+  for i := 0; i < len(s); i++ {
+    switch c := s[i]; c {
+    case '%':
+      dst[j] = unhex(s[i+1])<<4 | unhex(s[i+2])
+      i += 2
+    case '+':
+      dst[j] = ' '
+    default:
+      dst[j] = c
+    }
+    j++
+  }
+  return n, nil
+}
+
+// whether c must be escaped in a URL query (unreserved characters aren't)
+func shouldEscape(c byte) bool {
+  if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+    return false
+  }
+  switch c {
+  case '-', '_', '.', '~':
+    return false
+  }
+  return true
+}
+
+func isHex(c byte) bool {
+  return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+  switch {
+  case '0' <= c && c <= '9':
+    return c - '0'
+  case 'a' <= c && c <= 'f':
+    return c - 'a' + 10
+  default:
+    return c - 'A' + 10
+  }
+}
@@ -0,0 +1,80 @@
+package bytepool
+
+import (
+  "encoding/base64"
+  "encoding/hex"
+  . "gopkg.in/check.v1"
+  "io"
+  "net/url"
+)
+
+func (s *TestSuite) TestWritesBase64(c *C) {
+  for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawURLEncoding} {
+    item := newItem(40, nil)
+    data := []byte("over 9000?>")
+    n, err := item.WriteBase64(data, enc)
+    expected := enc.EncodeToString(data)
+
+    c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+    c.Assert(n, Equals, len(expected), Commentf("Expecting %d bytes written, got %d", len(expected), n))
+    c.Assert(item.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, item.String()))
+
+    decoded := newItem(40, nil)
+    decoded.WriteBase64Decoded(item.Bytes(), enc)
+    c.Assert(decoded.String(), Equals, "over 9000?>", Commentf("Expecting %q, got %q", "over 9000?>", decoded.String()))
+  }
+}
+
+func (s *TestSuite) TestWritesHex(c *C) {
+  item := newItem(40, nil)
+  item.WriteHex([]byte{0xde, 0xad, 0xbe, 0xef})
+  c.Assert(item.String(), Equals, "deadbeef", Commentf("Expecting %q, got %q", "deadbeef", item.String()))
+
+  decoded := newItem(4, nil)
+  n, err := decoded.WriteHexDecoded([]byte("DEADbeef"))
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, 4, Commentf("Expecting 4 bytes written, got %d", n))
+  c.Assert(decoded.Bytes(), DeepEquals, []byte{0xde, 0xad, 0xbe, 0xef})
+}
+
+func (s *TestSuite) TestWritesURLEscapedStrings(c *C) {
+  for _, input := range []string{"over 9000", "a&b=c/d?é~_.-", ""} {
+    item := newItem(60, nil)
+    item.WriteURLEscaped(input)
+    expected := url.QueryEscape(input)
+    c.Assert(item.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, item.String()))
+
+    unescaped := newItem(60, nil)
+    unescaped.WriteURLUnescaped(item.String())
+    c.Assert(unescaped.String(), Equals, input, Commentf("Expecting %q, got %q", input, unescaped.String()))
+  }
+}
+
+func (s *TestSuite) TestEncodingWritesReportOverflows(c *C) {
+  item := newItem(5, nil)
+  item.WriteString("!")
+
+  _, err := item.WriteBase64([]byte("over"), base64.StdEncoding)
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  _, err = item.WriteHex([]byte("over"))
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  _, err = item.WriteURLEscaped("a b/c")
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  _, err = item.WriteHexDecoded([]byte("0102030405"))
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  c.Assert(item.String(), Equals, "!", Commentf("Expecting nothing to be written, got %q", item.String()))
+}
+
+func (s *TestSuite) TestDecodingReportsMalformedInput(c *C) {
+  item := newItem(20, nil)
+
+  _, err := item.WriteBase64Decoded([]byte("!!!!"), base64.StdEncoding)
+  c.Assert(err, NotNil, Commentf("Expecting an error for invalid base64"))
+  _, err = item.WriteHexDecoded([]byte("zz"))
+  c.Assert(err, FitsTypeOf, hex.InvalidByteError(0))
+  _, err = item.WriteURLUnescaped("over%2")
+  c.Assert(err, Equals, url.EscapeError("%2"))
+  _, err = item.WriteURLUnescaped("%zz9000")
+  c.Assert(err, Equals, url.EscapeError("%zz"))
+  c.Assert(item.Len(), Equals, 0, Commentf("Expecting nothing to be written, got %q", item.String()))
+}
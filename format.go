@@ -0,0 +1,51 @@
+package bytepool
+
+import (
+  "fmt"
+  "io"
+  "strconv"
+)
+
+// The formatting methods append the text representation of a value directly
+// into the item. When it doesn't fit, nothing is written and io.ErrShortWrite
+// is returned
+
+// make the bytes appended to AvailableBuffer() part of the content
+func (item *Item) commit(b []byte) (int, error) {
+  if len(b) > item.Available() {
+    return 0, io.ErrShortWrite
+  }
+  item.length += len(b)
+  return len(b), nil
+}
+
+// write v in base 10
+func (item *Item) WriteInt(v int) (int, error) {
+  return item.commit(strconv.AppendInt(item.AvailableBuffer(), int64(v), 10))
+}
+
+// write v in base 10. Named apart from WriteInt64, which writes
+// the fixed-width binary encoding (see binary.go)
+func (item *Item) WriteDecimalInt64(v int64) (int, error) {
+  return item.commit(strconv.AppendInt(item.AvailableBuffer(), v, 10))
+}
+
+// write v in base 10, see WriteDecimalInt64
+func (item *Item) WriteDecimalUint64(v uint64) (int, error) {
+  return item.commit(strconv.AppendUint(item.AvailableBuffer(), v, 10))
+}
+
+// write f as formatted by strconv.FormatFloat(f, format, prec, 64)
+func (item *Item) WriteFloat(f float64, format byte, prec int) (int, error) {
+  return item.commit(strconv.AppendFloat(item.AvailableBuffer(), f, format, prec, 64))
+}
+
+// write s as a double-quoted Go string literal, see strconv.Quote
+func (item *Item) WriteQuoted(s string) (int, error) {
+  return item.commit(strconv.AppendQuote(item.AvailableBuffer(), s))
+}
+
+// write the arguments formatted according to format, see fmt.Printf
+func (item *Item) Writef(format string, args ...interface{}) (int, error) {
+  return item.commit(fmt.Appendf(item.AvailableBuffer(), format, args...))
+}
@@ -0,0 +1,68 @@
+package bytepool
+
+import (
+  . "gopkg.in/check.v1"
+  "io"
+  "testing"
+)
+
+func (s *TestSuite) TestWritesNumbers(c *C) {
+  expected := "9000 -9000 18446744073709551615 3.14 1e+06"
+  item := newItem(100, nil)
+  item.WriteInt(9000)
+  item.WriteByte(' ')
+  item.WriteDecimalInt64(-9000)
+  item.WriteByte(' ')
+  item.WriteDecimalUint64(1<<64-1)
+  item.WriteByte(' ')
+  item.WriteFloat(3.14159, 'f', 2)
+  item.WriteByte(' ')
+  item.WriteFloat(1000000, 'g', -1)
+
+  c.Assert(item.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, item.String()))
+}
+
+func (s *TestSuite) TestWritesQuotedStrings(c *C) {
+  expected := `"over \"9000\"\n"`
+  item := newItem(100, nil)
+  n, _ := item.WriteQuoted("over \"9000\"\n")
+
+  c.Assert(item.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, item.String()))
+  c.Assert(n, Equals, len(expected), Commentf("Expecting %d bytes written, got %d", len(expected), n))
+}
+
+func (s *TestSuite) TestWritesFormattedText(c *C) {
+  expected := "duncan: over 9000!"
+  item := newItem(100, nil)
+  item.Writef("%s: over %d!", "duncan", 9000)
+
+  c.Assert(item.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, item.String()))
+}
+
+func (s *TestSuite) TestFormattedWritesAreAllOrNothing(c *C) {
+  item := newItem(6, nil)
+  item.WriteString("over")
+
+  n, err := item.WriteInt(9000)
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  c.Assert(n, Equals, 0, Commentf("Expecting nothing written, got %d", n))
+  _, err = item.Writef(" %d", 9000)
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  c.Assert(item.String(), Equals, "over", Commentf("Expecting %q, got %q", "over", item.String()))
+
+  item.WriteInt(90)
+  c.Assert(item.String(), Equals, "over90", Commentf("Expecting %q, got %q", "over90", item.String()))
+}
+
+func (s *TestSuite) TestFormattingNumbersDoesNotAllocate(c *C) {
+  item := newItem(100, nil)
+  allocs := testing.AllocsPerRun(100, func() {
+    item.Reset()
+    item.WriteInt(9000)
+    item.WriteDecimalUint64(9000)
+    item.WriteFloat(3.14159, 'f', 2)
+    item.WriteQuoted("over 9000")
+  })
+
+  c.Assert(allocs, Equals, float64(0), Commentf("Expecting no allocation, got %v", allocs))
+}
@@ -0,0 +1,56 @@
+package bytepool
+
+import (
+  "bytes"
+  "hash"
+)
+
+// maintain h as a running hash of the content (e.g. crc32.NewIEEE() or
+// sha256.New()), starting with what has already been written. Write,
+// WriteString and ReadFrom update it as they go, other writes are caught
+// up with on Sum. Moving the end of the content back or editing it in place
+// makes the hash start over from the beginning of the content. Closing the
+// item resets h and stops tracking it
+func (item *Item) TrackHash(h hash.Hash) {
+  h.Reset()
+  item.hash = h
+  item.hashed = 0
+  item.syncHash()
+}
+
+// append the hash of the content to b, nil when no hash is tracked
+func (item *Item) Sum(b []byte) []byte {
+  if item.hash == nil {
+    return nil
+  }
+  item.syncHash()
+  return item.hash.Sum(b)
+}
+
+// tell whether the hash of the content is expected
+func (item *Item) Verify(expected []byte) bool {
+  if item.hash == nil {
+    return false
+  }
+  var sum [64]byte
+  return bytes.Equal(item.Sum(sum[:0]), expected)
+}
+
+// feed the content written since the last sync into the hash
+func (item *Item) syncHash() {
+  if item.hash == nil || item.hashed >= item.length {
+    return
+  }
+  item.hash.Write(item.bytes[item.hashed:item.length])
+  item.hashed = item.length
+}
+
+// start the hash over when the content from offset on is about to change
+// and part of it was already hashed
+func (item *Item) rehash(offset int) {
+  if item.hash == nil || offset >= item.hashed {
+    return
+  }
+  item.hash.Reset()
+  item.hashed = 0
+}
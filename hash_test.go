@@ -0,0 +1,58 @@
+package bytepool
+
+import (
+  "crypto/sha256"
+  "encoding/binary"
+  . "gopkg.in/check.v1"
+  "hash/crc32"
+  "strings"
+)
+
+func (s *TestSuite) TestTracksAHashOfTheContent(c *C) {
+  expected := sha256.Sum256([]byte("over 9000, the spice must flow"))
+  item := newItem(100, nil)
+  item.WriteString("over ")
+  item.TrackHash(sha256.New())
+  item.Write([]byte("9000"))
+  item.WriteByte(',')
+  item.ReadFrom(strings.NewReader(" the spice must flow"))
+
+  c.Assert(item.Sum(nil), DeepEquals, expected[:])
+  c.Assert(item.Verify(expected[:]), Equals, true, Commentf("Expecting the hash to be verified"))
+  c.Assert(item.Verify([]byte("nope")), Equals, false, Commentf("Expecting the hash not to be verified"))
+}
+
+func (s *TestSuite) TestTrackedHashFollowsEdits(c *C) {
+  item := newItem(100, nil)
+  item.TrackHash(crc32.NewIEEE())
+  item.WriteString("over 9000")
+  item.Sum(nil)
+  item.Position(4)
+  item.WriteString("!!")
+  c.Assert(item.Verify(crc32sum("over!!")), Equals, true, Commentf("Expecting the hash to follow Position"))
+
+  item.Insert(0, []byte("> "))
+  c.Assert(item.Verify(crc32sum("> over!!")), Equals, true, Commentf("Expecting the hash to follow Insert"))
+
+  item.PutUint16At(0, binary.BigEndian, 0x3c3c)
+  c.Assert(item.Verify(crc32sum("<<over!!")), Equals, true, Commentf("Expecting the hash to follow PutUint16At"))
+}
+
+func (s *TestSuite) TestCloseStopsTrackingTheHash(c *C) {
+  p := New(1, 100)
+  item := p.Checkout()
+  h := crc32.NewIEEE()
+  item.TrackHash(h)
+  item.WriteString("over 9000")
+  item.Close()
+
+  c.Assert(h.Sum32(), Equals, uint32(0), Commentf("Expecting the hash to be reset"))
+  item = p.Checkout()
+  defer item.Close()
+  c.Assert(item.Sum(nil), IsNil, Commentf("Expecting no hash to be tracked"))
+  c.Assert(item.Verify(nil), Equals, false)
+}
+
+func crc32sum(s string) []byte {
+  return binary.BigEndian.AppendUint32(nil, crc32.ChecksumIEEE([]byte(s)))
+}
@@ -0,0 +1,229 @@
+package bytepool
+
+import (
+  "context"
+  "errors"
+  "io"
+  "net/http"
+  "sort"
+  "strconv"
+)
+
+type bodyKey struct{}
+
+// Wraps next so that request bodies are read into an item checked out from
+// the smallest pool able to hold them (based on the request's ContentLength,
+// or the largest pool when it's unknown). The item is available to next via
+// Body(req) and is returned to its pool once next returns. Bodies larger than
+// the largest pool (or, when their length is unknown, which fill it before
+// io.EOF) are rejected with a 413 Request Entity Too Large
+func BodyHandler(next http.Handler, pools ...*Pool) http.Handler {
+  tiers := make([]*Pool, len(pools))
+  copy(tiers, pools)
+  sort.Slice(tiers, func(i, j int) bool { return tiers[i].capacity < tiers[j].capacity })
+  return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+    pool := pickTier(tiers, req.ContentLength)
+    if pool == nil {
+      http.Error(res, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+      return
+    }
+    item := pool.Checkout()
+    defer item.Close()
+
+    var err error
+    if req.ContentLength >= 0 {
+      _, err = item.ReadFromN(req.Body, req.ContentLength)
+    } else {
+      _, err = item.ReadFrom(req.Body)
+    }
+    if err != nil {
+      var maxBytesError *http.MaxBytesError
+      if err == ErrBufferFull || errors.As(err, &maxBytesError) {
+        http.Error(res, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+      } else {
+        http.Error(res, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+      }
+      return
+    }
+    req = req.WithContext(context.WithValue(req.Context(), bodyKey{}, item))
+    req.Body = io.NopCloser(item)
+    next.ServeHTTP(res, req)
+  })
+}
+
+// The request body read by BodyHandler, nil when the request
+// didn't go through one. Only valid until the handler returns
+func Body(req *http.Request) *Item {
+  item, _ := req.Context().Value(bodyKey{}).(*Item)
+  return item
+}
+
+// the smallest pool whose items can hold length bytes
+// or the largest pool when the length is unknown (-1)
+func pickTier(tiers []*Pool, length int64) *Pool {
+  if len(tiers) == 0 {
+    return nil
+  }
+  if length < 0 {
+    return tiers[len(tiers)-1]
+  }
+  for _, pool := range tiers {
+    if int64(pool.capacity) >= length {
+      return pool
+    }
+  }
+  return nil
+}
+
+// A http.ResponseWriter which buffers the response into a pooled item so that
+// headers (Content-Length, ETag, ...) can be set from the complete body, or the
+// response swapped for another one, before anything is sent. When the item
+// fills up, the response switches to streaming. Close sends the buffered
+// response and returns the item to its pool
+//    buffer: the item holding the body
+//    status: the status code given to WriteHeader (0 when not called)
+//    streaming: whether the response has been sent to the underlying writer
+type ResponseBuffer struct {
+  http.ResponseWriter
+  buffer    Buffer
+  status    int
+  streaming bool
+}
+
+func NewResponseBuffer(res http.ResponseWriter, buffer Buffer) *ResponseBuffer {
+  return &ResponseBuffer{
+    ResponseWriter: res,
+    buffer:         buffer,
+  }
+}
+
+// Wraps next so that its responses are buffered in items checked out from pool
+// when next panics, the buffered response is discarded rather than sent
+func BufferedHandler(next http.Handler, pool *Pool) http.Handler {
+  return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+    buffer := NewResponseBuffer(res, pool.Checkout())
+    defer func() {
+      if err := recover(); err != nil {
+        buffer.Discard()
+        panic(err)
+      }
+      buffer.Close()
+    }()
+    next.ServeHTTP(buffer, req)
+  })
+}
+
+// record the status code, it's only sent once the response is complete
+// (or once it switches to streaming)
+func (r *ResponseBuffer) WriteHeader(status int) {
+  if r.streaming {
+    r.ResponseWriter.WriteHeader(status)
+    return
+  }
+  if r.status == 0 {
+    r.status = status
+  }
+}
+
+// buffer b, or switch to streaming when it doesn't fit
+func (r *ResponseBuffer) Write(b []byte) (int, error) {
+  if r.streaming {
+    return r.ResponseWriter.Write(b)
+  }
+  if len(b) <= r.buffer.Available() {
+    return r.buffer.Write(b)
+  }
+  if err := r.stream(); err != nil {
+    return 0, err
+  }
+  return r.ResponseWriter.Write(b)
+}
+
+// the buffered body. Like JsonItem.Bytes, it drops a JsonItem's trailing
+// delimiter, so with a JsonItem it's meant to be called once the body is complete
+func (r *ResponseBuffer) Bytes() []byte {
+  return r.buffer.Bytes()
+}
+
+// the status code which will be sent
+func (r *ResponseBuffer) Status() int {
+  if r.status == 0 {
+    return http.StatusOK
+  }
+  return r.status
+}
+
+// tell whether the response has already been (partially) sent
+func (r *ResponseBuffer) Streaming() bool {
+  return r.streaming
+}
+
+// discard the status code and the buffered body so that another response
+// can be written. Headers are kept. Returns false when the response is
+// already streaming
+func (r *ResponseBuffer) Reset() bool {
+  if r.streaming {
+    return false
+  }
+  r.status = 0
+  r.buffer.Position(0)
+  return true
+}
+
+// switch to streaming and flush the underlying writer
+func (r *ResponseBuffer) Flush() {
+  if r.stream() != nil {
+    return
+  }
+  if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+    flusher.Flush()
+  }
+}
+
+// the underlying writer, for http.ResponseController
+func (r *ResponseBuffer) Unwrap() http.ResponseWriter {
+  return r.ResponseWriter
+}
+
+// send the buffered response (with a Content-Length unless one was set)
+// and return the item to its pool. Since a deferred Close also runs when
+// the handler panics, code which recovers should call Discard instead
+func (r *ResponseBuffer) Close() error {
+  defer r.buffer.Close()
+  if r.streaming {
+    return nil
+  }
+  r.streaming = true
+  body := r.buffer.Bytes()
+  status := r.Status()
+  header := r.Header()
+  if header.Get("Content-Length") == "" && status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified {
+    header.Set("Content-Length", strconv.Itoa(len(body)))
+  }
+  r.ResponseWriter.WriteHeader(status)
+  if len(body) == 0 {
+    return nil
+  }
+  _, err := r.ResponseWriter.Write(body)
+  return err
+}
+
+// return the item to its pool without sending what's buffered
+// (a response which is already streaming is left incomplete)
+func (r *ResponseBuffer) Discard() {
+  r.buffer.Position(0)
+  r.buffer.Close()
+}
+
+// send the status code and whatever is buffered, as is since more is to
+// follow, further writes go straight to the underlying writer
+func (r *ResponseBuffer) stream() error {
+  if r.streaming {
+    return nil
+  }
+  r.streaming = true
+  r.ResponseWriter.WriteHeader(r.Status())
+  _, err := r.buffer.WriteTo(r.ResponseWriter)
+  r.buffer.Position(0)
+  return err
+}
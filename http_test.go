@@ -0,0 +1,225 @@
+package bytepool
+
+import (
+  "fmt"
+  . "gopkg.in/check.v1"
+  "io"
+  "log"
+  "net/http"
+  "net/http/httptest"
+  "strconv"
+  "strings"
+)
+
+func echoBody(res http.ResponseWriter, req *http.Request) {
+  res.Write(Body(req).Bytes())
+}
+
+func (s *TestSuite) TestBodyHandlerReadsTheBodyIntoTheSmallestTier(c *C) {
+  small, large := New(1, 8), New(1, 64)
+  var capacity int
+  handler := BodyHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+    capacity = cap(Body(req).Raw())
+    echoBody(res, req)
+  }), large, small)
+
+  res := httptest.NewRecorder()
+  handler.ServeHTTP(res, httptest.NewRequest("POST", "/", strings.NewReader("over 9000")))
+
+  c.Assert(res.Code, Equals, 200, Commentf("Expecting a 200, got %d", res.Code))
+  c.Assert(res.Body.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", res.Body.String()))
+  c.Assert(capacity, Equals, 64, Commentf("Expecting the large tier to be used, got a capacity of %d", capacity))
+  c.Assert(large.Len(), Equals, 1, Commentf("Expecting the item to be returned to the pool"))
+
+  res = httptest.NewRecorder()
+  handler.ServeHTTP(res, httptest.NewRequest("POST", "/", strings.NewReader("hello")))
+  c.Assert(res.Body.String(), Equals, "hello", Commentf("Expecting %q, got %q", "hello", res.Body.String()))
+  c.Assert(capacity, Equals, 8, Commentf("Expecting the small tier to be used, got a capacity of %d", capacity))
+  c.Assert(small.Len(), Equals, 1, Commentf("Expecting the item to be returned to the pool"))
+}
+
+func (s *TestSuite) TestBodyHandlerExposesTheBodyAsTheRequestsBody(c *C) {
+  handler := BodyHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+    io.Copy(res, req.Body)
+  }), New(1, 16))
+
+  res := httptest.NewRecorder()
+  handler.ServeHTTP(res, httptest.NewRequest("POST", "/", strings.NewReader("over 9000")))
+  c.Assert(res.Body.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", res.Body.String()))
+}
+
+func (s *TestSuite) TestBodyHandlerRejectsLargeBodies(c *C) {
+  called := false
+  pool := New(1, 4)
+  handler := BodyHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+    called = true
+  }), pool)
+
+  res := httptest.NewRecorder()
+  handler.ServeHTTP(res, httptest.NewRequest("POST", "/", strings.NewReader("over 9000")))
+  c.Assert(res.Code, Equals, 413, Commentf("Expecting a 413, got %d", res.Code))
+
+  req := httptest.NewRequest("POST", "/", strings.NewReader("over 9000"))
+  req.ContentLength = -1
+  res = httptest.NewRecorder()
+  handler.ServeHTTP(res, req)
+  c.Assert(res.Code, Equals, 413, Commentf("Expecting a 413, got %d", res.Code))
+
+  c.Assert(called, Equals, false, Commentf("Expecting the handler not to be called"))
+  c.Assert(pool.Len(), Equals, 1, Commentf("Expecting the item to be returned to the pool"))
+}
+
+func (s *TestSuite) TestBodyHandlerReadsBodiesOfUnknownLength(c *C) {
+  handler := BodyHandler(http.HandlerFunc(echoBody), New(1, 4), New(1, 16))
+  req := httptest.NewRequest("POST", "/", strings.NewReader("over 9000"))
+  req.ContentLength = -1
+
+  res := httptest.NewRecorder()
+  handler.ServeHTTP(res, req)
+  c.Assert(res.Body.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", res.Body.String()))
+}
+
+func (s *TestSuite) TestBodyHandlerRejectsShortBodies(c *C) {
+  handler := BodyHandler(http.HandlerFunc(echoBody), New(1, 16))
+  req := httptest.NewRequest("POST", "/", strings.NewReader("over"))
+  req.ContentLength = 9
+
+  res := httptest.NewRecorder()
+  handler.ServeHTTP(res, req)
+  c.Assert(res.Code, Equals, 400, Commentf("Expecting a 400, got %d", res.Code))
+}
+
+func (s *TestSuite) TestBodyIsNilOutsideOfABodyHandler(c *C) {
+  c.Assert(Body(httptest.NewRequest("GET", "/", nil)), IsNil)
+}
+
+func (s *TestSuite) TestResponseBufferSetsTheContentLength(c *C) {
+  pool := New(1, 32)
+  handler := BufferedHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+    res.WriteHeader(201)
+    io.WriteString(res, "over ")
+    io.WriteString(res, "9000")
+  }), pool)
+
+  res := httptest.NewRecorder()
+  handler.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+  c.Assert(res.Code, Equals, 201, Commentf("Expecting a 201, got %d", res.Code))
+  c.Assert(res.Body.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", res.Body.String()))
+  c.Assert(res.Header().Get("Content-Length"), Equals, "9")
+  c.Assert(pool.Len(), Equals, 1, Commentf("Expecting the item to be returned to the pool"))
+}
+
+func (s *TestSuite) TestResponseBufferCanComputeHeadersFromTheBody(c *C) {
+  res := httptest.NewRecorder()
+  buffer := NewResponseBuffer(res, New(1, 32).Checkout())
+  io.WriteString(buffer, "over 9000")
+  buffer.Header().Set("ETag", strconv.Quote(string(buffer.Bytes()[:4])))
+  c.Assert(res.Body.Len(), Equals, 0, Commentf("Expecting nothing to be sent yet, got %q", res.Body.String()))
+
+  buffer.Close()
+  c.Assert(res.Header().Get("ETag"), Equals, `"over"`)
+  c.Assert(res.Body.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", res.Body.String()))
+}
+
+func (s *TestSuite) TestResponseBufferCanBeReset(c *C) {
+  res := httptest.NewRecorder()
+  buffer := NewResponseBuffer(res, New(1, 32).Checkout())
+  io.WriteString(buffer, `{"partial":`)
+  c.Assert(buffer.Reset(), Equals, true, Commentf("Expecting the buffer to be reset"))
+  http.Error(buffer, "oops", 500)
+  buffer.Close()
+
+  c.Assert(res.Code, Equals, 500, Commentf("Expecting a 500, got %d", res.Code))
+  c.Assert(res.Body.String(), Equals, "oops\n", Commentf("Expecting %q, got %q", "oops\n", res.Body.String()))
+}
+
+func (s *TestSuite) TestResponseBufferStreamsWhenFull(c *C) {
+  expected := "the spice must flow"
+  pool := New(1, 8)
+  res := httptest.NewRecorder()
+  buffer := NewResponseBuffer(res, pool.Checkout())
+  io.WriteString(buffer, "the ")
+  io.WriteString(buffer, "spice ")
+
+  c.Assert(buffer.Streaming(), Equals, true, Commentf("Expecting the response to be streaming"))
+  c.Assert(buffer.Reset(), Equals, false, Commentf("Expecting a streaming response not to be reset"))
+  io.WriteString(buffer, "must flow")
+  buffer.Close()
+
+  c.Assert(res.Body.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, res.Body.String()))
+  c.Assert(res.Header().Get("Content-Length"), Equals, "")
+  c.Assert(pool.Len(), Equals, 1, Commentf("Expecting the item to be returned to the pool"))
+}
+
+func (s *TestSuite) TestResponseBufferWorksWithAJsonItem(c *C) {
+  pool := NewJson(1, 32)
+  res := httptest.NewRecorder()
+  item := pool.Checkout()
+  buffer := NewResponseBuffer(res, item)
+  item.BeginObject()
+  item.WriteKeyInt("over", 9000)
+  item.EndObject()
+  buffer.Close()
+
+  c.Assert(res.Body.String(), Equals, `{"over":9000}`, Commentf("Expecting %q, got %q", `{"over":9000}`, res.Body.String()))
+  c.Assert(res.Header().Get("Content-Length"), Equals, "13")
+  c.Assert(pool.Len(), Equals, 1, Commentf("Expecting the item to be returned to the pool"))
+}
+
+func (s *TestSuite) TestResponseBufferWritesThroughToAJsonItem(c *C) {
+  pool := NewJson(1, 32)
+  res := httptest.NewRecorder()
+  buffer := NewResponseBuffer(res, pool.Checkout())
+  fmt.Fprint(buffer, "[1,")
+  fmt.Fprint(buffer, "2]")
+  buffer.Close()
+
+  c.Assert(res.Body.String(), Equals, "[1,2]", Commentf("Expecting %q, got %q", "[1,2]", res.Body.String()))
+  c.Assert(res.Header().Get("Content-Length"), Equals, "5")
+  c.Assert(pool.Len(), Equals, 1, Commentf("Expecting the item to be returned to the pool"))
+}
+
+func (s *TestSuite) TestResponseBufferStreamsAJsonItemAsIs(c *C) {
+  pool := NewJson(1, 4)
+  res := httptest.NewRecorder()
+  buffer := NewResponseBuffer(res, pool.Checkout())
+  fmt.Fprint(buffer, "[1,")
+  fmt.Fprint(buffer, "2,3]")
+  buffer.Close()
+
+  c.Assert(res.Body.String(), Equals, "[1,2,3]", Commentf("Expecting %q, got %q", "[1,2,3]", res.Body.String()))
+}
+
+func (s *TestSuite) TestBufferedHandlerDiscardsTheResponseOnPanic(c *C) {
+  pool := New(1, 8192)
+  server := httptest.NewUnstartedServer(BufferedHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+    io.WriteString(res, strings.Repeat("x", 5000))
+    panic("over 9000")
+  }), pool))
+  server.Config.ErrorLog = log.New(io.Discard, "", 0)
+  server.Start()
+  defer server.Close()
+
+  res, err := http.Get(server.URL)
+  if err == nil {
+    _, err = io.ReadAll(res.Body)
+    res.Body.Close()
+  }
+  c.Assert(err, NotNil, Commentf("Expecting the response to fail"))
+  c.Assert(pool.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", pool.Len()))
+}
+
+func (s *TestSuite) TestBufferedHandlerRepanics(c *C) {
+  pool := New(1, 100)
+  handler := BufferedHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+    io.WriteString(res, "over 9000")
+    panic("over 9000")
+  }), pool)
+  res := httptest.NewRecorder()
+
+  c.Assert(func() { handler.ServeHTTP(res, httptest.NewRequest("GET", "/", nil)) }, PanicMatches, "over 9000")
+  c.Assert(res.Body.Len(), Equals, 0, Commentf("Expecting nothing to be sent, got %q", res.Body.String()))
+  c.Assert(res.Header().Get("Content-Length"), Equals, "")
+  c.Assert(pool.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", pool.Len()))
+}
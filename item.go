@@ -1,69 +1,176 @@
 package bytepool
 
 import (
+  "bytes"
+  "errors"
+  "hash"
   "io"
+  "sync/atomic"
+  "unicode/utf8"
+)
+
+// returned by ReadFrom when the reader has more data than fits in the item
+var ErrBufferFull = errors.New("bytepool: buffer full")
+
+var (
+  errNegativeOffset = errors.New("bytepool: negative offset")
+  errInvalidSeek    = errors.New("bytepool: seek outside of the content")
+  errInvalidWhence  = errors.New("bytepool: invalid whence")
+  errUnreadByte     = errors.New("bytepool: UnreadByte: previous operation was not a successful read")
+  errUnreadRune     = errors.New("bytepool: UnreadRune: previous operation was not a successful ReadRune")
 )
 
 // a slice of bytes within the pool
 //    pool: points to the pool containing this item
+//    refs: no of references to the item, it's returned to the pool at 0
 //    length:
 //    read:
+//    lastRead: size of the last ReadRune, -1 after reading bytes, 0 when
+//              nothing can be unread
+//    truncated: whether the last ReadFrom stopped because the slice was full before io.EOF
+//    hash: the running hash of the content, see TrackHash
+//    hashed: no of bytes of content written into hash
 //    bytes: the slice
 type Item struct {
-  pool   *Pool
+  pool      *Pool
+  refs      int32
+  length    int
+  read      int
+  lastRead  int
+  truncated bool
+  hash      hash.Hash
+  hashed    int
+  bytes     []byte
+}
+
+// a savepoint, see Mark and Rollback
+//    length, read: the item's cursors
+//    depth, added: a JsonItem's nesting state
+type Mark struct {
   length int
   read   int
-  bytes  []byte
+  depth  int
+  added  bool
 }
 
 func newItem(capacity int, pool *Pool) *Item {
   return &Item{
     pool:  pool,
+    refs:  1,
     bytes: make([]byte, capacity),
   }
 }
 
+// write b into the slice, when it doesn't fit, as much as possible
+// is written and io.ErrShortWrite is returned
 func (item *Item) Write(b []byte) (int, error) {
-  if item.Full() {
-    return 0, io.ErrShortWrite
-  }
   n := copy(item.bytes[item.length:], b)
   item.length += n
+  item.syncHash()
+  if n < len(b) {
+    return n, io.ErrShortWrite
+  }
   return n, nil
 }
 
-func (item *Item) WriteByte(b byte) bool {
+// write a single byte into the slice, returns io.ErrShortWrite when full
+func (item *Item) WriteByte(b byte) error {
   if item.Full() {
-    return false
+    return io.ErrShortWrite
   }
   item.bytes[item.length] = b
   item.length += 1
-  return true
+  return nil
 }
 
-func (item *Item) WriteString(s string) int {
-  if item.Full() {
-    return 0
-  }
+// write s into the slice, when it doesn't fit, as much as possible
+// is written and io.ErrShortWrite is returned
+func (item *Item) WriteString(s string) (int, error) {
   n := copy(item.bytes[item.length:], s)
   item.length += n
-  return n
+  item.syncHash()
+  if n < len(s) {
+    return n, io.ErrShortWrite
+  }
+  return n, nil
 }
 
-// read data from an io.Reader into the item's slice
+// write the UTF-8 encoding of r into the slice, nothing is
+// written and io.ErrShortWrite is returned when it doesn't fit
+func (item *Item) WriteRune(r rune) (int, error) {
+  if r >= 0 && r < utf8.RuneSelf {
+    if err := item.WriteByte(byte(r)); err != nil {
+      return 0, err
+    }
+    return 1, nil
+  }
+  var encoded [utf8.UTFMax]byte
+  n := utf8.EncodeRune(encoded[:], r)
+  if n > item.Available() {
+    return 0, io.ErrShortWrite
+  }
+  return item.Write(encoded[:n])
+}
+
+// read data from an io.Reader into the item's slice until io.EOF
+// when the slice fills up before io.EOF, the reader isn't read any further
+// (it may or may not have more data): Truncated() becomes true and
+// ErrBufferFull is returned. Use ReadFromN when the length is known. A reader
+// which keeps returning no data and no error results in io.ErrNoProgress
 func (item *Item) ReadFrom(reader io.Reader) (int64, error) {
-  var read int64
-  for {
-    r, err := reader.Read(item.bytes[item.length:])
-    read += int64(r)
-    item.length += r
-    if err == io.EOF || item.Full() {
-      return read, nil
+  defer item.syncHash()
+  item.truncated = false
+  n, err := item.fill(reader, cap(item.bytes))
+  if err == io.EOF {
+    return int64(n), nil
+  }
+  if err != nil {
+    return int64(n), err
+  }
+  item.truncated = true
+  return int64(n), ErrBufferFull
+}
+
+// read exactly n bytes from an io.Reader into the item's slice, meant
+// for bodies of a known length (e.g. Content-Length). Returns ErrBufferFull
+// without reading when n bytes don't fit, and like io.ReadFull, io.EOF when
+// nothing was read or io.ErrUnexpectedEOF when only part of it was
+func (item *Item) ReadFromN(reader io.Reader, n int64) (int64, error) {
+  if n > int64(cap(item.bytes)-item.length) {
+    return 0, ErrBufferFull
+  }
+  defer item.syncHash()
+  read, err := item.fill(reader, item.length+int(n))
+  if err == io.EOF {
+    if read == 0 {
+      return 0, io.EOF
     }
+    if int64(read) < n {
+      return int64(read), io.ErrUnexpectedEOF
+    }
+    err = nil
+  }
+  return int64(read), err
+}
+
+// read from reader until the slice is filled up to limit, or until it
+// returns an error (including io.EOF) or stops making progress
+func (item *Item) fill(reader io.Reader, limit int) (int, error) {
+  read, empty := 0, 0
+  for item.length < limit {
+    r, err := reader.Read(item.bytes[item.length:limit])
+    read += r
+    item.length += r
     if err != nil {
       return read, err
     }
+    if r > 0 {
+      empty = 0
+    } else if empty++; empty == maxConsecutiveEmptyReads {
+      return read, io.ErrNoProgress
+    }
   }
+  return read, nil
 }
 
 // read data from the item in to another byte-slice
@@ -73,12 +180,156 @@ func (item *Item) Read(p []byte) (int, error) {
   }
   n := copy(p, item.bytes[item.read:item.length])
   item.read += n
+  item.lastRead = -1
   if item.Drained() {
     return n, io.EOF
   }
   return n, nil
 }
 
+// write the unread content to w
+func (item *Item) WriteTo(w io.Writer) (int64, error) {
+  item.lastRead = 0
+  unread := item.bytes[item.read:item.length]
+  if len(unread) == 0 {
+    return 0, nil
+  }
+  n, err := w.Write(unread)
+  item.read += n
+  if err == nil && n < len(unread) {
+    err = io.ErrShortWrite
+  }
+  return int64(n), err
+}
+
+// read from the content, starting at off, without moving the read cursor
+func (item *Item) ReadAt(p []byte, off int64) (int, error) {
+  if off < 0 {
+    return 0, errNegativeOffset
+  }
+  if off >= int64(item.length) {
+    return 0, io.EOF
+  }
+  n := copy(p, item.bytes[off:item.length])
+  if n < len(p) {
+    return n, io.EOF
+  }
+  return n, nil
+}
+
+// move the read cursor within the content
+func (item *Item) Seek(offset int64, whence int) (int64, error) {
+  var position int64
+  switch whence {
+  case io.SeekStart:
+    position = offset
+  case io.SeekCurrent:
+    position = int64(item.read) + offset
+  case io.SeekEnd:
+    position = int64(item.length) + offset
+  default:
+    return 0, errInvalidWhence
+  }
+  if position < 0 || position > int64(item.length) {
+    return 0, errInvalidSeek
+  }
+  item.read = int(position)
+  item.lastRead = 0
+  return position, nil
+}
+
+// read the next unread byte
+func (item *Item) ReadByte() (byte, error) {
+  if item.Drained() {
+    item.lastRead = 0
+    return 0, io.EOF
+  }
+  b := item.bytes[item.read]
+  item.read++
+  item.lastRead = -1
+  return b, nil
+}
+
+// step the read cursor back by one byte, following a successful read
+func (item *Item) UnreadByte() error {
+  if item.lastRead == 0 {
+    return errUnreadByte
+  }
+  item.lastRead = 0
+  if item.read > 0 {
+    item.read--
+  }
+  return nil
+}
+
+// read the next unread UTF-8 encoded rune
+func (item *Item) ReadRune() (rune, int, error) {
+  if item.Drained() {
+    item.lastRead = 0
+    return 0, 0, io.EOF
+  }
+  if c := item.bytes[item.read]; c < utf8.RuneSelf {
+    item.read++
+    item.lastRead = 1
+    return rune(c), 1, nil
+  }
+  r, size := utf8.DecodeRune(item.bytes[item.read:item.length])
+  item.read += size
+  item.lastRead = size
+  return r, size, nil
+}
+
+// step the read cursor back by one rune, following a successful ReadRune
+func (item *Item) UnreadRune() error {
+  if item.lastRead <= 0 {
+    return errUnreadRune
+  }
+  item.read -= item.lastRead
+  item.lastRead = 0
+  return nil
+}
+
+// return the next n unread bytes (or fewer when not available) and advance
+// the read cursor, the slice is only valid until the next write
+func (item *Item) Next(n int) []byte {
+  item.lastRead = 0
+  if unread := item.length - item.read; n > unread {
+    n = unread
+  }
+  data := item.bytes[item.read : item.read+n]
+  item.read += n
+  if n > 0 {
+    item.lastRead = -1
+  }
+  return data
+}
+
+// read until the first occurrence of delim, returning a copy of the data
+// including the delimiter, or io.EOF when the delimiter wasn't found
+func (item *Item) ReadBytes(delim byte) ([]byte, error) {
+  line, err := item.readSlice(delim)
+  return append([]byte(nil), line...), err
+}
+
+// read until the first occurrence of delim, see ReadBytes
+func (item *Item) ReadString(delim byte) (string, error) {
+  line, err := item.readSlice(delim)
+  return string(line), err
+}
+
+func (item *Item) readSlice(delim byte) ([]byte, error) {
+  var err error
+  end := bytes.IndexByte(item.bytes[item.read:item.length], delim) + 1
+  if end == 0 {
+    end = item.length - item.read
+    err = io.EOF
+  }
+  line := item.bytes[item.read : item.read+end]
+  item.read += end
+  item.lastRead = -1
+  return line, err
+}
+
 // return only the content that has been read so far
 func (item *Item) Bytes() []byte {
   return item.bytes[0:item.length]
@@ -112,24 +363,196 @@ func (item *Item) Position(position int) bool {
   if position < 0 || position > cap(item.bytes) {
     return false
   }
+  item.rehash(position)
   item.length = position
+  if item.read > position {
+    item.read = position
+  }
+  item.lastRead = 0
+  return true
+}
+
+// no of bytes which can still be written
+func (item *Item) Available() int {
+  return cap(item.bytes) - item.length
+}
+
+// an empty slice whose capacity is the unused part of the item, meant to be
+// appended to and passed to Write
+func (item *Item) AvailableBuffer() []byte {
+  return item.bytes[item.length:item.length]
+}
+
+// capacity of the item
+func (item *Item) Cap() int {
+  return cap(item.bytes)
+}
+
+// make room for n more bytes, by discarding the content which has already
+// been read if needed. Since items don't grow, it returns false when n bytes
+// can't be made available (the read content is still discarded)
+func (item *Item) Grow(n int) bool {
+  if n < 0 {
+    panic("bytepool.Item.Grow: negative count")
+  }
+  if item.Available() >= n {
+    return true
+  }
+  if item.read > 0 {
+    item.rehash(0)
+    item.length = copy(item.bytes, item.bytes[item.read:item.length])
+    item.read = 0
+    item.lastRead = 0
+  }
+  return item.Available() >= n
+}
+
+// discard all but the first n unread bytes, panics when n is negative
+// or greater than the no of unread bytes
+func (item *Item) Truncate(n int) {
+  if n == 0 {
+    item.Reset()
+    return
+  }
+  item.lastRead = 0
+  if n < 0 || n > item.length-item.read {
+    panic("bytepool.Item.Truncate: truncation out of range")
+  }
+  item.rehash(item.read + n)
+  item.length = item.read + n
+}
+
+// create a savepoint of the item's cursors, to be restored with Rollback
+func (item *Item) Mark() Mark {
+  return Mark{length: item.length, read: item.read}
+}
+
+// restore the cursors saved by Mark, discarding whatever was written since
+// returns false, and does nothing, when the content has been shrunk below
+// the mark (e.g. by Reset or Position) since
+func (item *Item) Rollback(mark Mark) bool {
+  if mark.length > item.length {
+    return false
+  }
+  item.rehash(mark.length)
+  item.length = mark.length
+  item.read = mark.read
+  item.lastRead = 0
   return true
 }
 
+// empty the item, keeping its slice
+func (item *Item) Reset() {
+  item.rehash(0)
+  item.length = 0
+  item.read = 0
+  item.lastRead = 0
+  item.truncated = false
+}
+
 func (item *Item) Full() bool {
   return item.length == cap(item.bytes)
 }
 
+// tell whether the last ReadFrom stopped because the slice was full before
+// reaching io.EOF, in which case the rest of the data is still in the reader
+func (item *Item) Truncated() bool {
+  return item.truncated
+}
+
 // tell whether all the content in the slice has been read
 func (item *Item) Drained() bool {
   return item.length == item.read
 }
 
-// close the item and return it to the pool
+// a copy of the item (content and read cursor) which isn't managed by any
+// pool, its capacity is the length of the content
+func (item *Item) Clone() *Item {
+  clone := newItem(item.length, nil)
+  clone.length = copy(clone.bytes, item.bytes[:item.length])
+  clone.read = item.read
+  return clone
+}
+
+// replace dst's content (and read cursor) with a copy of the item's, when
+// it doesn't fit, dst is left untouched and ErrBufferFull is returned
+func (item *Item) CopyTo(dst *Item) error {
+  if dst == item {
+    return nil
+  }
+  if item.length > cap(dst.bytes) {
+    return ErrBufferFull
+  }
+  dst.Reset()
+  dst.length = copy(dst.bytes, item.bytes[:item.length])
+  dst.read = item.read
+  return nil
+}
+
+// take the item out of pool management so that its content can be kept
+// after the request is done (e.g. for async processing): it will not go back
+// to the pool, which counts it as a loss and creates a replacement.
+// Returns the content, which isn't affected by closing the item. An item
+// which was already released (and possibly back in the pool) can't be
+// detached, nil is returned
+func (item *Item) Detach() []byte {
+  if atomic.LoadInt32(&item.refs) <= 0 {
+    return nil
+  }
+  if pool := item.pool; pool != nil {
+    item.pool = nil
+    pool.replace(item)
+  }
+  return item.Bytes()
+}
+
+// add a reference to the item, which then needs one more Release (or Close)
+// before going back to the pool. Meant for sharing an item between goroutines
+// it panics when the item was already released (and possibly back in the pool)
+func (item *Item) Retain() *Item {
+  for {
+    refs := atomic.LoadInt32(&item.refs)
+    if refs <= 0 {
+      panic("bytepool.Item.Retain: item already released")
+    }
+    if atomic.CompareAndSwapInt32(&item.refs, refs, refs+1) {
+      return item
+    }
+  }
+}
+
+// drop a reference to the item, the same as Close
+func (item *Item) Release() {
+  item.Close()
+}
+
+// drop a reference, resetting the item when it was the last one
+// references are never dropped below 0, so extra releases are ignored
+func (item *Item) release() bool {
+  for {
+    refs := atomic.LoadInt32(&item.refs)
+    if refs <= 0 {
+      return false
+    }
+    if atomic.CompareAndSwapInt32(&item.refs, refs, refs-1) {
+      if refs > 1 {
+        return false
+      }
+      break
+    }
+  }
+  item.Reset()
+  item.hash = nil
+  return true
+}
+
+// drop a reference to the item, once all references are dropped
+// the item is reset and returned to the pool. Closing an item which is
+// already back in the pool does nothing, but an item must still only be
+// closed once per reference: once it's checked out again, a late Close
+// returns the new owner's item to the pool
 func (item *Item) Close() error {
-  item.length = 0
-  item.read = 0
-  if item.pool != nil {
+  if item.release() && item.pool != nil {
     item.pool.list <- item
   }
   return nil
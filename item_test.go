@@ -2,8 +2,15 @@ package bytepool
 
 import (
   "bytes"
+  "fmt"
   "io"
   . "gopkg.in/check.v1"
+  "net/http"
+  "net/http/httptest"
+  "strconv"
+  "strings"
+  "testing/iotest"
+  "time"
 )
 
 func (s *TestSuite) TestCanWriteAString(c *C) {
@@ -190,3 +197,385 @@ func (s *TestSuite) TestReadDoesNotAutomaticallyRewind(c *C) {
   c.Assert(err, Equals, io.EOF, Commentf("error should be io.EOF, got %v", err))
   c.Assert(string(b[0:5]), Equals, "hello", Commentf("expecting to have read `hello`, got %v", string(b[0:5])))
 }
+
+func (s *TestSuite) TestWriteReportsAShortWrite(c *C) {
+  item := newItem(5, nil)
+  n, err := item.Write([]byte("hello world"))
+
+  c.Assert(n, Equals, 5, Commentf("Expecting 5 bytes written, got %d", n))
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  c.Assert(item.String(), Equals, "hello", Commentf("Expecting %q, got %q", "hello", item.String()))
+}
+
+func (s *TestSuite) TestWriteStringReportsAShortWrite(c *C) {
+  item := newItem(5, nil)
+  n, err := item.WriteString("hel")
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+
+  n, err = item.WriteString("lo world")
+  c.Assert(n, Equals, 2, Commentf("Expecting 2 bytes written, got %d", n))
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+}
+
+func (s *TestSuite) TestWriteByteReportsAShortWrite(c *C) {
+  item := newItem(1, nil)
+  c.Assert(item.WriteByte('a'), IsNil)
+  c.Assert(item.WriteByte('b'), Equals, io.ErrShortWrite)
+}
+
+func (s *TestSuite) TestItemWorksWithTheStandardWriterInterfaces(c *C) {
+  item := newItem(20, nil)
+  var _ io.ByteWriter = item
+  var _ io.StringWriter = item
+  fmt.Fprintf(item, "over %d", 9000)
+  io.WriteString(item, "!")
+
+  c.Assert(item.String(), Equals, "over 9000!", Commentf("Expecting %q, got %q", "over 9000!", item.String()))
+}
+
+func (s *TestSuite) TestReadFromReportsAnOverflow(c *C) {
+  item := newItem(5, nil)
+  n, err := item.ReadFrom(strings.NewReader("hello world"))
+
+  c.Assert(n, Equals, int64(5), Commentf("Expecting 5 bytes read, got %d", n))
+  c.Assert(err, Equals, ErrBufferFull, Commentf("Expecting ErrBufferFull, got %v", err))
+  c.Assert(item.Truncated(), Equals, true, Commentf("Expecting the item to be truncated"))
+  c.Assert(item.String(), Equals, "hello", Commentf("Expecting %q, got %q", "hello", item.String()))
+
+  item.Close()
+  c.Assert(item.Truncated(), Equals, false, Commentf("Expecting Close to reset the truncated flag"))
+}
+
+func (s *TestSuite) TestReadFromAnExactSizeEndingWithEOFIsNotTruncated(c *C) {
+  item := newItem(5, nil)
+  _, err := item.ReadFrom(iotest.DataErrReader(strings.NewReader("hello")))
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(item.Truncated(), Equals, false, Commentf("Expecting the item not to be truncated"))
+}
+
+func (s *TestSuite) TestReadFromDoesNotReadOnceFull(c *C) {
+  item := newItem(5, nil)
+  reader, writer := io.Pipe()
+  go writer.Write([]byte("hello"))
+  n, err := item.ReadFrom(reader)
+
+  c.Assert(n, Equals, int64(5), Commentf("Expecting 5 bytes read, got %d", n))
+  c.Assert(err, Equals, ErrBufferFull, Commentf("Expecting ErrBufferFull, got %v", err))
+  c.Assert(item.Truncated(), Equals, true, Commentf("Expecting the item to be truncated"))
+  writer.Close()
+}
+
+func (s *TestSuite) TestReadFromGivesUpOnAReaderThatMakesNoProgress(c *C) {
+  item := newItem(5, nil)
+  _, err := item.ReadFrom(emptyReader{})
+  c.Assert(err, Equals, io.ErrNoProgress, Commentf("Expecting io.ErrNoProgress, got %v", err))
+
+  item.WriteString("hel")
+  _, err = item.ReadFrom(emptyReader{})
+  c.Assert(err, Equals, io.ErrNoProgress, Commentf("Expecting io.ErrNoProgress, got %v", err))
+}
+
+func (s *TestSuite) TestReadFromReturnsTheReadersError(c *C) {
+  item := newItem(20, nil)
+  n, err := item.ReadFrom(iotest.TimeoutReader(strings.NewReader("hello world")))
+
+  c.Assert(n, Equals, int64(11), Commentf("Expecting 11 bytes read, got %d", n))
+  c.Assert(err, Equals, iotest.ErrTimeout, Commentf("Expecting iotest.ErrTimeout, got %v", err))
+}
+
+func (s *TestSuite) TestReadFromNReadsExactlyN(c *C) {
+  item := newItem(10, nil)
+  reader := strings.NewReader("hello world")
+  n, err := item.ReadFromN(reader, 5)
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, int64(5), Commentf("Expecting 5 bytes read, got %d", n))
+  c.Assert(item.String(), Equals, "hello", Commentf("Expecting %q, got %q", "hello", item.String()))
+  c.Assert(reader.Len(), Equals, 6, Commentf("Expecting 6 bytes left in the reader, got %d", reader.Len()))
+}
+
+func (s *TestSuite) TestReadFromNFailsWhenNDoesNotFit(c *C) {
+  item := newItem(4, nil)
+  n, err := item.ReadFromN(strings.NewReader("hello"), 5)
+
+  c.Assert(err, Equals, ErrBufferFull, Commentf("Expecting ErrBufferFull, got %v", err))
+  c.Assert(n, Equals, int64(0), Commentf("Expecting nothing read, got %d", n))
+}
+
+func (s *TestSuite) TestReadFromNReportsAShortBody(c *C) {
+  item := newItem(10, nil)
+  n, err := item.ReadFromN(strings.NewReader("hel"), 5)
+  c.Assert(err, Equals, io.ErrUnexpectedEOF, Commentf("Expecting io.ErrUnexpectedEOF, got %v", err))
+  c.Assert(n, Equals, int64(3), Commentf("Expecting 3 bytes read, got %d", n))
+
+  n, err = item.ReadFromN(strings.NewReader(""), 5)
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+}
+
+func (s *TestSuite) TestWriteToWritesTheUnreadContent(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  item.Read(make([]byte, 5))
+  buffer := new(bytes.Buffer)
+  n, err := item.WriteTo(buffer)
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, int64(4), Commentf("Expecting 4 bytes written, got %d", n))
+  c.Assert(buffer.String(), Equals, "9000", Commentf("Expecting %q, got %q", "9000", buffer.String()))
+  c.Assert(item.Drained(), Equals, true, Commentf("Expecting the item to be drained"))
+}
+
+func (s *TestSuite) TestReadAtDoesNotMoveTheReadCursor(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  b := make([]byte, 3)
+
+  n, err := item.ReadAt(b, 5)
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(string(b[:n]), Equals, "900", Commentf("Expecting %q, got %q", "900", b[:n]))
+
+  n, err = item.ReadAt(b, 7)
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+  c.Assert(string(b[:n]), Equals, "00", Commentf("Expecting %q, got %q", "00", b[:n]))
+
+  _, err = item.ReadAt(b, -1)
+  c.Assert(err, NotNil, Commentf("Expecting an error for a negative offset"))
+  c.Assert(item.Drained(), Equals, false, Commentf("Expecting the item not to be drained"))
+}
+
+func (s *TestSuite) TestSeekMovesTheReadCursor(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+
+  size, _ := item.Seek(0, io.SeekEnd)
+  c.Assert(size, Equals, int64(9), Commentf("Expecting a size of 9, got %d", size))
+
+  item.Seek(5, io.SeekStart)
+  item.Seek(-1, io.SeekCurrent)
+  rest, _ := io.ReadAll(item)
+  c.Assert(string(rest), Equals, " 9000", Commentf("Expecting %q, got %q", " 9000", rest))
+
+  _, err := item.Seek(10, io.SeekStart)
+  c.Assert(err, NotNil, Commentf("Expecting an error when seeking beyond the content"))
+  _, err = item.Seek(-1, io.SeekStart)
+  c.Assert(err, NotNil, Commentf("Expecting an error when seeking before the content"))
+}
+
+func (s *TestSuite) TestReadsAndUnreadsBytes(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("ab")
+  c.Assert(item.UnreadByte(), NotNil, Commentf("Expecting an error when nothing was read"))
+
+  b, _ := item.ReadByte()
+  c.Assert(b, Equals, byte('a'), Commentf("Expecting 'a', got %q", b))
+  c.Assert(item.UnreadByte(), IsNil)
+  c.Assert(item.UnreadByte(), NotNil, Commentf("Expecting an error when unreading twice"))
+
+  b, _ = item.ReadByte()
+  c.Assert(b, Equals, byte('a'), Commentf("Expecting 'a', got %q", b))
+  b, _ = item.ReadByte()
+  c.Assert(b, Equals, byte('b'), Commentf("Expecting 'b', got %q", b))
+  _, err := item.ReadByte()
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+}
+
+func (s *TestSuite) TestReadsAndUnreadsRunes(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("a€")
+
+  r, size, _ := item.ReadRune()
+  c.Assert(r, Equals, 'a', Commentf("Expecting 'a', got %q", r))
+  c.Assert(size, Equals, 1, Commentf("Expecting a size of 1, got %d", size))
+
+  r, size, _ = item.ReadRune()
+  c.Assert(r, Equals, '€', Commentf("Expecting '€', got %q", r))
+  c.Assert(size, Equals, 3, Commentf("Expecting a size of 3, got %d", size))
+
+  c.Assert(item.UnreadRune(), IsNil)
+  c.Assert(item.UnreadRune(), NotNil, Commentf("Expecting an error when unreading twice"))
+  r, _, _ = item.ReadRune()
+  c.Assert(r, Equals, '€', Commentf("Expecting '€', got %q", r))
+
+  _, _, err := item.ReadRune()
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+}
+
+func (s *TestSuite) TestPositionKeepsTheReadCursorWithinTheContent(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  io.ReadAll(item)
+  item.Position(4)
+
+  c.Assert(item.Drained(), Equals, true, Commentf("Expecting the item to be drained"))
+  n, err := item.Read(make([]byte, 4))
+  c.Assert(n, Equals, 0, Commentf("Expecting nothing to be read, got %d", n))
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+}
+
+func (s *TestSuite) TestItemCanBeServedAsContent(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  res := httptest.NewRecorder()
+  req := httptest.NewRequest("GET", "/", nil)
+  req.Header.Set("Range", "bytes=5-")
+  http.ServeContent(res, req, "power.txt", time.Time{}, item)
+
+  c.Assert(res.Code, Equals, 206, Commentf("Expecting a 206, got %d", res.Code))
+  c.Assert(res.Body.String(), Equals, "9000", Commentf("Expecting %q, got %q", "9000", res.Body.String()))
+}
+
+func (s *TestSuite) TestResetEmptiesTheItem(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  item.ReadByte()
+  item.Reset()
+
+  c.Assert(item.Len(), Equals, 0, Commentf("Expecting a length of 0, got %d", item.Len()))
+  c.Assert(item.Drained(), Equals, true, Commentf("Expecting the item to be drained"))
+  c.Assert(item.Available(), Equals, 20, Commentf("Expecting 20 available bytes, got %d", item.Available()))
+}
+
+func (s *TestSuite) TestTruncateKeepsTheFirstNUnreadBytes(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  item.Next(2)
+  item.Truncate(3)
+
+  c.Assert(item.String(), Equals, "over ", Commentf("Expecting %q, got %q", "over ", item.String()))
+  c.Assert(func() { item.Truncate(4) }, PanicMatches, ".*out of range")
+}
+
+func (s *TestSuite) TestNextReturnsTheNextUnreadBytes(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+
+  c.Assert(string(item.Next(5)), Equals, "over ")
+  c.Assert(string(item.Next(10)), Equals, "9000")
+  c.Assert(len(item.Next(1)), Equals, 0)
+}
+
+func (s *TestSuite) TestReadBytesAndReadString(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("a,bc,def")
+
+  line, err := item.ReadBytes(',')
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(string(line), Equals, "a,", Commentf("Expecting %q, got %q", "a,", line))
+
+  str, err := item.ReadString(',')
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(str, Equals, "bc,", Commentf("Expecting %q, got %q", "bc,", str))
+
+  str, err = item.ReadString(',')
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+  c.Assert(str, Equals, "def", Commentf("Expecting %q, got %q", "def", str))
+}
+
+func (s *TestSuite) TestAvailableBufferCanBeAppendedTo(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over ")
+  item.Write(strconv.AppendInt(item.AvailableBuffer(), 9000, 10))
+
+  c.Assert(item.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", item.String()))
+  c.Assert(item.Cap(), Equals, 20, Commentf("Expecting a capacity of 20, got %d", item.Cap()))
+  c.Assert(item.Available(), Equals, 11, Commentf("Expecting 11 available bytes, got %d", item.Available()))
+}
+
+func (s *TestSuite) TestWriteRune(c *C) {
+  item := newItem(4, nil)
+  n, err := item.WriteRune('a')
+  c.Assert(n, Equals, 1, Commentf("Expecting 1 byte written, got %d", n))
+
+  n, err = item.WriteRune('€')
+  c.Assert(n, Equals, 3, Commentf("Expecting 3 bytes written, got %d", n))
+  c.Assert(item.String(), Equals, "a€", Commentf("Expecting %q, got %q", "a€", item.String()))
+
+  n, err = item.WriteRune('€')
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+  c.Assert(item.Len(), Equals, 4, Commentf("Expecting a partial rune not to be written"))
+}
+
+func (s *TestSuite) TestGrowDiscardsTheReadContent(c *C) {
+  item := newItem(10, nil)
+  item.WriteString("over 9000")
+  item.Next(5)
+  c.Assert(item.Grow(1), Equals, true)
+  c.Assert(item.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", item.String()))
+
+  c.Assert(item.Grow(5), Equals, true)
+  c.Assert(item.String(), Equals, "9000", Commentf("Expecting %q, got %q", "9000", item.String()))
+  c.Assert(item.Available(), Equals, 6, Commentf("Expecting 6 available bytes, got %d", item.Available()))
+  c.Assert(item.Grow(7), Equals, false, Commentf("Expecting Grow to fail"))
+  c.Assert(item.String(), Equals, "9000", Commentf("Expecting %q, got %q", "9000", item.String()))
+}
+
+func (s *TestSuite) TestGrowCompactsEvenWhenItFails(c *C) {
+  item := newItem(10, nil)
+  item.WriteString("over 9000")
+  item.Next(5)
+
+  c.Assert(item.Grow(20), Equals, false, Commentf("Expecting Grow to fail"))
+  c.Assert(item.Available(), Equals, 6, Commentf("Expecting 6 available bytes, got %d", item.Available()))
+}
+
+func (s *TestSuite) TestRollbackRestoresTheCursors(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over ")
+  item.ReadByte()
+  mark := item.Mark()
+  item.WriteString("9000")
+  item.ReadByte()
+
+  c.Assert(item.Rollback(mark), Equals, true, Commentf("Expecting the rollback to succeed"))
+  c.Assert(item.String(), Equals, "over ", Commentf("Expecting %q, got %q", "over ", item.String()))
+  rest, _ := io.ReadAll(item)
+  c.Assert(string(rest), Equals, "ver ", Commentf("Expecting %q, got %q", "ver ", rest))
+}
+
+func (s *TestSuite) TestRollbackFailsWhenTheContentShrunk(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  mark := item.Mark()
+  item.Position(4)
+
+  c.Assert(item.Rollback(mark), Equals, false, Commentf("Expecting the rollback to fail"))
+  c.Assert(item.String(), Equals, "over", Commentf("Expecting %q, got %q", "over", item.String()))
+}
+
+func (s *TestSuite) TestCloneCopiesTheContent(c *C) {
+  p := New(1, 20)
+  item := p.Checkout()
+  item.WriteString("over 9000")
+  item.Next(5)
+  clone := item.Clone()
+  item.Close()
+  p.Checkout().WriteString("overwritten")
+
+  c.Assert(clone.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", clone.String()))
+  c.Assert(clone.Cap(), Equals, 9, Commentf("Expecting a capacity of 9, got %d", clone.Cap()))
+  c.Assert(string(clone.Unread()), Equals, "9000", Commentf("Expecting the read cursor to be copied"))
+  clone.Close()
+  c.Assert(p.Len(), Equals, 0, Commentf("Expecting the clone not to go into the pool"))
+}
+
+func (s *TestSuite) TestCopyToReplacesTheContent(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  large, small := newItem(30, nil), newItem(5, nil)
+  large.WriteString("previous")
+  small.WriteString("small")
+
+  c.Assert(item.CopyTo(large), IsNil)
+  c.Assert(large.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", large.String()))
+  c.Assert(item.CopyTo(small), Equals, ErrBufferFull)
+  c.Assert(small.String(), Equals, "small", Commentf("Expecting %q, got %q", "small", small.String()))
+}
+
+func (s *TestSuite) TestCopyToItselfKeepsTheContent(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+
+  c.Assert(item.CopyTo(item), IsNil)
+  c.Assert(item.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", item.String()))
+}
@@ -0,0 +1,89 @@
+package bytepool
+
+import (
+  "io"
+)
+
+// A reader over an item's content with its own offset, independent from the
+// item's read cursor and from other readers, so that one item can be read by
+// several consumers. Readers see later writes, and must not be used once
+// the item is closed
+//    item: the item being read
+//    offset: position of the next byte to read
+type ItemReader struct {
+  item   *Item
+  offset int
+}
+
+// create a reader positioned at the start of the item's content
+func (item *Item) NewReader() *ItemReader {
+  return &ItemReader{item: item}
+}
+
+func (r *ItemReader) Read(p []byte) (int, error) {
+  if r.offset >= r.item.length {
+    return 0, io.EOF
+  }
+  n := copy(p, r.item.bytes[r.offset:r.item.length])
+  r.offset += n
+  return n, nil
+}
+
+func (r *ItemReader) ReadByte() (byte, error) {
+  if r.offset >= r.item.length {
+    return 0, io.EOF
+  }
+  b := r.item.bytes[r.offset]
+  r.offset++
+  return b, nil
+}
+
+func (r *ItemReader) ReadAt(p []byte, off int64) (int, error) {
+  return r.item.ReadAt(p, off)
+}
+
+// write the content which this reader hasn't read yet to w
+func (r *ItemReader) WriteTo(w io.Writer) (int64, error) {
+  if r.offset >= r.item.length {
+    return 0, nil
+  }
+  unread := r.item.bytes[r.offset:r.item.length]
+  n, err := w.Write(unread)
+  r.offset += n
+  if err == nil && n < len(unread) {
+    err = io.ErrShortWrite
+  }
+  return int64(n), err
+}
+
+func (r *ItemReader) Seek(offset int64, whence int) (int64, error) {
+  var position int64
+  switch whence {
+  case io.SeekStart:
+    position = offset
+  case io.SeekCurrent:
+    position = int64(r.offset) + offset
+  case io.SeekEnd:
+    position = int64(r.item.length) + offset
+  default:
+    return 0, errInvalidWhence
+  }
+  if position < 0 || position > int64(r.item.length) {
+    return 0, errInvalidSeek
+  }
+  r.offset = int(position)
+  return position, nil
+}
+
+// no of bytes this reader hasn't read yet
+func (r *ItemReader) Len() int {
+  if r.offset >= r.item.length {
+    return 0
+  }
+  return r.item.length - r.offset
+}
+
+// move back to the start of the content
+func (r *ItemReader) Rewind() {
+  r.offset = 0
+}
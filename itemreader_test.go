@@ -0,0 +1,64 @@
+package bytepool
+
+import (
+  "bytes"
+  . "gopkg.in/check.v1"
+  "io"
+)
+
+func (s *TestSuite) TestItemReadersHaveTheirOwnOffset(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  reader1 := item.NewReader()
+  reader2 := item.NewReader()
+
+  b := make([]byte, 5)
+  reader1.Read(b)
+  c.Assert(string(b), Equals, "over ", Commentf("Expecting %q, got %q", "over ", b))
+  c.Assert(reader1.Len(), Equals, 4, Commentf("Expecting 4 unread bytes, got %d", reader1.Len()))
+
+  all, _ := io.ReadAll(reader2)
+  c.Assert(string(all), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", all))
+  all, _ = io.ReadAll(reader1)
+  c.Assert(string(all), Equals, "9000", Commentf("Expecting %q, got %q", "9000", all))
+  all, _ = io.ReadAll(item)
+  c.Assert(string(all), Equals, "over 9000", Commentf("Expecting the item's cursor to be untouched, got %q", all))
+}
+
+func (s *TestSuite) TestItemReaderCanBeRewound(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  reader := item.NewReader()
+  io.ReadAll(reader)
+
+  _, err := reader.ReadByte()
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+
+  reader.Rewind()
+  b, _ := reader.ReadByte()
+  c.Assert(b, Equals, byte('o'), Commentf("Expecting 'o', got %q", b))
+}
+
+func (s *TestSuite) TestItemReaderWritesTo(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  reader := item.NewReader()
+  reader.Seek(5, io.SeekStart)
+  buffer := new(bytes.Buffer)
+  n, err := reader.WriteTo(buffer)
+
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, int64(4), Commentf("Expecting 4 bytes written, got %d", n))
+  c.Assert(buffer.String(), Equals, "9000", Commentf("Expecting %q, got %q", "9000", buffer.String()))
+}
+
+func (s *TestSuite) TestItemReaderSeesNewWrites(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over")
+  reader := item.NewReader()
+  io.ReadAll(reader)
+  item.WriteString(" 9000")
+
+  all, _ := io.ReadAll(reader)
+  c.Assert(string(all), Equals, " 9000", Commentf("Expecting %q, got %q", " 9000", all))
+}
@@ -0,0 +1,66 @@
+package bytepool
+
+import (
+  "bufio"
+  "bytes"
+  "iter"
+)
+
+// iterate over the lines of the content, without their end-of-line
+// marker (\n or \r\n), e.g. the records of an NDJSON body
+func (item *Item) Lines() iter.Seq[[]byte] {
+  return item.Scan(bufio.ScanLines)
+}
+
+// iterate over the records of the content delimited by sep (which isn't
+// included), a trailing sep doesn't produce an empty record
+func (item *Item) Split(sep []byte) iter.Seq[[]byte] {
+  return item.Scan(ScanSeparator(sep))
+}
+
+// iterate over the tokens split (as used by a bufio.Scanner) finds in the
+// content. The tokens are slices of the item, only valid until it's modified
+func (item *Item) Scan(split bufio.SplitFunc) iter.Seq[[]byte] {
+  return func(yield func([]byte) bool) {
+    data := item.Bytes()
+    for len(data) > 0 {
+      advance, token, err := split(data, true)
+      if err != nil {
+        if err == bufio.ErrFinalToken && token != nil {
+          yield(token)
+        }
+        return
+      }
+      if advance <= 0 {
+        if token != nil {
+          yield(token)
+        }
+        return
+      }
+      data = data[advance:]
+      if token != nil && !yield(token) {
+        return
+      }
+    }
+  }
+}
+
+// a bufio.SplitFunc returning the records delimited by sep, without it
+// an empty sep splits the data into bytes
+func ScanSeparator(sep []byte) bufio.SplitFunc {
+  if len(sep) == 0 {
+    return bufio.ScanBytes
+  }
+  return func(data []byte, atEOF bool) (int, []byte, error) {
+    if atEOF && len(data) == 0 {
+      return 0, nil, nil
+    }
+    if i := bytes.Index(data, sep); i >= 0 {
+      return i + len(sep), data[:i], nil
+    }
+    if atEOF {
+      return len(data), data, nil
+    }
+    return 0, nil, nil
+  }
+}
@@ -0,0 +1,81 @@
+package bytepool
+
+import (
+  "bufio"
+  . "gopkg.in/check.v1"
+  "iter"
+  "strings"
+  "testing"
+)
+
+func collect(seq iter.Seq[[]byte]) []string {
+  var records []string
+  for record := range seq {
+    records = append(records, string(record))
+  }
+  return records
+}
+
+func (s *TestSuite) TestIteratesOverLines(c *C) {
+  item := newItem(100, nil)
+  item.WriteString("{\"id\":1}\n{\"id\":2}\r\n\n{\"id\":3}\n")
+  expected := []string{`{"id":1}`, `{"id":2}`, ``, `{"id":3}`}
+
+  c.Assert(collect(item.Lines()), DeepEquals, expected)
+}
+
+func (s *TestSuite) TestIteratesOverSeparatedRecords(c *C) {
+  item := newItem(100, nil)
+  item.WriteString("a,,bc,def")
+  expected := []string{"a", "", "bc", "def"}
+
+  c.Assert(collect(item.Split([]byte(","))), DeepEquals, expected)
+
+  item.WriteString(",")
+  c.Assert(collect(item.Split([]byte(","))), DeepEquals, expected)
+}
+
+func (s *TestSuite) TestIterationCanStopEarly(c *C) {
+  item := newItem(100, nil)
+  item.WriteString("a\nb\nc\n")
+  var records []string
+  for line := range item.Lines() {
+    records = append(records, string(line))
+    if len(records) == 2 {
+      break
+    }
+  }
+
+  c.Assert(records, DeepEquals, []string{"a", "b"})
+}
+
+func (s *TestSuite) TestIteratesWithAnySplitFunc(c *C) {
+  item := newItem(100, nil)
+  item.WriteString("  the spice\tmust  flow ")
+
+  c.Assert(collect(item.Scan(bufio.ScanWords)), DeepEquals, []string{"the", "spice", "must", "flow"})
+}
+
+func (s *TestSuite) TestScanSeparatorWorksWithAScanner(c *C) {
+  scanner := bufio.NewScanner(strings.NewReader("a||bc||def"))
+  scanner.Split(ScanSeparator([]byte("||")))
+  var records []string
+  for scanner.Scan() {
+    records = append(records, scanner.Text())
+  }
+
+  c.Assert(records, DeepEquals, []string{"a", "bc", "def"})
+}
+
+func (s *TestSuite) TestIteratingDoesNotAllocate(c *C) {
+  item := newItem(100, nil)
+  item.WriteString("a\nb\nc\n")
+  count := 0
+  allocs := testing.AllocsPerRun(100, func() {
+    for range item.Lines() {
+      count++
+    }
+  })
+
+  c.Assert(allocs, Equals, float64(0), Commentf("Expecting no allocation, got %v", allocs))
+}
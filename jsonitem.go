@@ -1,8 +1,10 @@
 package bytepool
 
 import (
+  "io"
   "strconv"
   "strings"
+  "sync/atomic"
   "time"
 )
 
@@ -27,25 +29,48 @@ func newJsonItem(capacity int, pool *JsonPool) *JsonItem {
 var JsonEncode = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
 
 // Write arbitary string into the slice
-func (item *JsonItem) WriteString(s string) int {
-  return item.WriteSafeString(JsonEncode(s))
+// like io.StringWriter, returns the no of bytes of s written (not counting
+// the quotes and escapes) and io.ErrShortWrite when the quoted string doesn't fit
+func (item *JsonItem) WriteString(s string) (int, error) {
+  encoded := JsonEncode(s)
+  n := item.WriteSafeString(encoded)
+  if n < len(encoded)+2 {
+    return encodedPrefix(s, n-1), io.ErrShortWrite
+  }
+  return len(s), nil
+}
+
+// no of bytes of s whose escaped form fits in the first n escaped bytes
+func encodedPrefix(s string, n int) int {
+  i := 0
+  for ; i < len(s); i++ {
+    size := 1
+    if s[i] == '\\' || s[i] == '"' {
+      size = 2
+    }
+    if n < size {
+      break
+    }
+    n -= size
+  }
+  return i
 }
 
 // Write arbitary int into the slice
 func (item *JsonItem) WriteInt(value int) int {
-  n := item.Item.WriteString(strconv.Itoa(value))
+  n, _ := item.Item.WriteString(strconv.Itoa(value))
   return item.delimit(n)
 }
 
 // Write bool value into the slice
 func (item *JsonItem) WriteBool(value bool) int {
-  n := item.Item.WriteString(strconv.FormatBool(value))
+  n, _ := item.Item.WriteString(strconv.FormatBool(value))
   return item.delimit(n)
 }
 
 // Write time value (in RFC3339, or yyyy-mm-ddThh:mm:ssZ
 func (item *JsonItem) WriteTime(value time.Time) int {
-  return item.WriteString(value.Format(time.RFC3339))
+  return item.WriteSafeString(value.Format(time.RFC3339))
 }
 
 // Write an safe (doesn't need escaping) string into the slice
@@ -83,7 +108,7 @@ func (item *JsonItem) WriteKeyTime(key string, value time.Time) int {
 // Write a partial(to `[` char) key-value pair where value is an array
 func (item *JsonItem) WriteKeyArray(key string) int {
   n := item.writeString(key, false)
-  if item.WriteByte(byte(':')) {
+  if item.WriteByte(byte(':')) == nil {
     n++
   }
   if item.BeginArray() {
@@ -95,7 +120,7 @@ func (item *JsonItem) WriteKeyArray(key string) int {
 // Write a partial(to `{` char) key-value pair where value is an object
 func (item *JsonItem) WriteKeyObject(key string) int {
   n := item.writeString(key, false)
-  if item.WriteByte(byte(':')) {
+  if item.WriteByte(byte(':')) == nil {
     n++
   }
   if item.BeginObject() {
@@ -107,17 +132,18 @@ func (item *JsonItem) WriteKeyObject(key string) int {
 // Write a key-value pair where value is any string-casted values
 func (item *JsonItem) WriteKeyValue(key, value string) int {
   n := item.writeString(key, false)
-  if item.WriteByte(byte(':')) {
+  if item.WriteByte(byte(':')) == nil {
     n++
   }
-  n += item.Item.WriteString(value)
+  written, _ := item.Item.WriteString(value)
+  n += written
   return item.delimit(n)
 }
 
 
 // Write a string & quotes into the slice.Typically used to write keys
 func (item *JsonItem) writeString(s string, delimit bool) int {
-  n := item.Item.WriteString(`"` + s + `"`)
+  n, _ := item.Item.WriteString(`"` + s + `"`)
   if delimit == false {
     return n
   }
@@ -128,7 +154,7 @@ func (item *JsonItem) writeString(s string, delimit bool) int {
 func (item *JsonItem) BeginArray() bool {
   item.added = false
   item.depth++
-  return item.WriteByte('[')
+  return item.WriteByte('[') == nil
 }
 
 // Write Array end: `]` into the slice and decrease depth
@@ -142,7 +168,7 @@ func (item *JsonItem) EndArray() (int, error) {
 // Write Object start: `{` into the slice and increase depth
 func (item *JsonItem) BeginObject() bool {
   item.depth++
-  return item.WriteByte('{')
+  return item.WriteByte('{') == nil
 }
 
 // Write Object start: `}` into the slice and decrease depth
@@ -158,21 +184,86 @@ func (item *JsonItem) delimit(length int) int {
   if item.depth == 0 {
     return length
   }
-  item.WriteByte(',')
+  if item.WriteByte(',') != nil {
+    return length
+  }
   return length + 1
 }
 
-// Close the JsonItem and return it to the pool
-func (item *JsonItem) Close() error {
+// Return an unpooled copy of the meaningful bytes, see Item.Clone
+func (item *JsonItem) Clone() *Item {
+  item.TrimLastIf(',')
+  return item.Item.Clone()
+}
+
+// Copy the meaningful bytes into dst, see Item.CopyTo
+func (item *JsonItem) CopyTo(dst *Item) error {
   item.TrimLastIf(',')
-  item.Item.Close()
+  return item.Item.CopyTo(dst)
+}
+
+// Take the JsonItem out of pool management, see Item.Detach
+func (item *JsonItem) Detach() []byte {
+  if atomic.LoadInt32(&item.refs) <= 0 {
+    return nil
+  }
+  if pool := item.pool; pool != nil {
+    item.pool = nil
+    pool.replace(item)
+  }
+  return item.Bytes()
+}
+
+// Add a reference to the JsonItem, see Item.Retain
+func (item *JsonItem) Retain() *JsonItem {
+  item.Item.Retain()
+  return item
+}
+
+// Drop a reference to the JsonItem, the same as Close
+func (item *JsonItem) Release() {
+  item.Close()
+}
+
+// Drop a reference to the JsonItem, once all references are dropped
+// the JsonItem is reset and returned to the pool
+func (item *JsonItem) Close() error {
+  if !item.Item.release() {
+    return nil
+  }
+  item.depth = 0
+  item.added = false
   if item.pool != nil {
-    item.depth = 0
     item.pool.list <- item
   }
   return nil
 }
 
+// Create a savepoint of the cursors and the nesting state
+func (item *JsonItem) Mark() Mark {
+  mark := item.Item.Mark()
+  mark.depth = item.depth
+  mark.added = item.added
+  return mark
+}
+
+// Restore the cursors and the nesting state saved by Mark, see Item.Rollback
+func (item *JsonItem) Rollback(mark Mark) bool {
+  if !item.Item.Rollback(mark) {
+    return false
+  }
+  item.depth = mark.depth
+  item.added = mark.added
+  return true
+}
+
+// Empty the JsonItem and its nesting state
+func (item *JsonItem) Reset() {
+  item.Item.Reset()
+  item.depth = 0
+  item.added = false
+}
+
 func (item *JsonItem) Len() int {
   item.TrimLastIf(',')
   return item.Item.Len()
@@ -1,6 +1,7 @@
 package bytepool
 
 import (
+  "io"
   "time"
   . "gopkg.in/check.v1"
 )
@@ -126,3 +127,64 @@ func (s *TestSuite) TestJsonNestedObjects(c *C) {
 
   c.Assert(actual, Equals, expected, Commentf("Expecting %q, got %q", expected, actual))
 }
+
+func (s *TestSuite) TestJsonWriteStringReportsAShortWrite(c *C) {
+  item := newJsonItem(8, nil)
+  n, err := item.WriteString(`o"ver 9000`)
+
+  c.Assert(n, Equals, 6, Commentf("Expecting 6 bytes written, got %d", n))
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+}
+
+func (s *TestSuite) TestJsonWriteStringReportsTheBytesOfTheString(c *C) {
+  item := newJsonItem(100, nil)
+  item.BeginArray()
+  n, err := io.WriteString(item, `a"b`)
+
+  c.Assert(err, IsNil)
+  c.Assert(n, Equals, 3, Commentf("Expecting 3 bytes written, got %d", n))
+  c.Assert(item.String(), Equals, `["a\"b"`, Commentf("Expecting %q, got %q", `["a\"b"`, item.String()))
+}
+
+func (s *TestSuite) TestJsonResetClearsTheNestingState(c *C) {
+  item := newJsonItem(100, nil)
+  item.BeginArray()
+  item.WriteInt(1)
+  item.Reset()
+  item.WriteInt(2)
+
+  c.Assert(item.String(), Equals, "2", Commentf("Expecting %q, got %q", "2", item.String()))
+}
+
+func (s *TestSuite) TestJsonRollbackRestoresTheNestingState(c *C) {
+  expected := `[1,{"a":2}]`
+  item := newJsonItem(100, nil)
+  item.BeginArray()
+  item.WriteInt(1)
+  item.BeginObject()
+  item.WriteKeyInt("a", 2)
+  item.EndObject()
+  mark := item.Mark()
+  item.BeginObject()
+  item.WriteKeyArray("b")
+  item.WriteInt(3)
+
+  c.Assert(item.Rollback(mark), Equals, true, Commentf("Expecting the rollback to succeed"))
+  item.EndArray()
+  actual := item.String()
+  c.Assert(actual, Equals, expected, Commentf("Expecting %q, got %q", expected, actual))
+  c.Assert(item.depth, Equals, 0, Commentf("Expecting a depth of 0, got %d", item.depth))
+}
+
+func (s *TestSuite) TestJsonCloneAndCopyToDropTheTrailingDelimiter(c *C) {
+  item := newJsonItem(100, nil)
+  item.BeginArray()
+  item.WriteInt(9000)
+  item.EndArray()
+  clone := item.Clone()
+  c.Assert(clone.String(), Equals, "[9000]", Commentf("Expecting %q, got %q", "[9000]", clone.String()))
+
+  dst := newItem(10, nil)
+  c.Assert(item.CopyTo(dst), IsNil)
+  c.Assert(dst.String(), Equals, "[9000]", Commentf("Expecting %q, got %q", "[9000]", dst.String()))
+}
@@ -7,6 +7,7 @@ import (
 // A specialized Pool for making json
 type JsonPool struct {
   misses   int32
+  losses   int32
   capacity int
   list     chan *JsonItem
 }
@@ -31,13 +32,36 @@ func (pool *JsonPool) Checkout() *JsonItem {
     atomic.AddInt32(&pool.misses, 1)
     item = newJsonItem(pool.capacity, nil)
   }
+  atomic.StoreInt32(&item.refs, 1)
   return item
 }
 
+// stop managing item (which is checked out) and put a new one in its place
+func (pool *JsonPool) replace(item *JsonItem) {
+  atomic.AddInt32(&pool.losses, 1)
+  pool.list <- newJsonItem(pool.capacity, pool)
+}
+
 func (pool *JsonPool) Len() int {
   return len(pool.list)
 }
 
-func (pool *JsonPool) Misses() int32 {
-  return atomic.LoadInt32(&pool.misses)
+func (pool *JsonPool) Misses() int {
+  return int(atomic.LoadInt32(&pool.misses))
+}
+
+// no of items taken out of the pool by Detach
+func (pool *JsonPool) Losses() int {
+  return int(atomic.LoadInt32(&pool.losses))
+}
+
+// a snapshot of the pool's counters
+func (pool *JsonPool) Stats() Stats {
+  return Stats{
+    Capacity:  pool.capacity,
+    Count:     cap(pool.list),
+    Available: pool.Len(),
+    Misses:    pool.Misses(),
+    Losses:    pool.Losses(),
+  }
 }
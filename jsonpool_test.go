@@ -38,3 +38,72 @@ func (s *TestSuite) TestJsonPoolReleasesAnItemBackIntoThePool(c *C) {
 
   c.Assert(reflect.ValueOf(item2).Pointer(), Equals, pointer, Commentf("Pool returned an unexpected item"))
 }
+
+func (s *TestSuite) TestJsonPoolStats(c *C) {
+  p := NewJson(2, 10)
+  item1 := p.Checkout()
+  item2 := p.Checkout()
+  item3 := p.Checkout()
+
+  stats := p.Stats()
+  expected := Stats{Capacity: 10, Count: 2, Available: 0, Misses: 1}
+  c.Assert(stats, Equals, expected, Commentf("Expecting %+v, got %+v", expected, stats))
+
+  item1.Close()
+  item2.Close()
+  item3.Close()
+}
+
+func (s *TestSuite) TestJsonPoolRetainedItemsReturnOnTheLastRelease(c *C) {
+  p := NewJson(1, 20)
+  item := p.Checkout()
+  item.BeginArray()
+  item.Retain()
+
+  item.Close()
+  c.Assert(p.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.Len()))
+
+  item.Release()
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+  c.Assert(item.depth, Equals, 0, Commentf("Expecting a depth of 0, got %d", item.depth))
+}
+
+func (s *TestSuite) TestJsonPoolReplacesDetachedItems(c *C) {
+  p := NewJson(1, 20)
+  item := p.Checkout()
+  item.BeginArray()
+  item.WriteInt(9000)
+  item.EndArray()
+  detached := item.Detach()
+  item.Close()
+
+  c.Assert(string(detached), Equals, "[9000]", Commentf("Expecting %q, got %q", "[9000]", detached))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+  c.Assert(p.Losses(), Equals, 1, Commentf("Expecting 1 loss, got %d", p.Losses()))
+  c.Assert(p.Checkout() == item, Equals, false, Commentf("Expecting the detached item not to be reused"))
+}
+
+func (s *TestSuite) TestJsonPoolSlicesReturnTheItemOnTheLastRelease(c *C) {
+  p := NewJson(1, 20)
+  item := p.Checkout()
+  item.BeginArray()
+  item.WriteInt(9000)
+  item.EndArray()
+  slice := item.Slice(1, 5)
+  item.Close()
+
+  c.Assert(slice.String(), Equals, "9000", Commentf("Expecting %q, got %q", "9000", slice.String()))
+  c.Assert(p.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.Len()))
+  slice.Release()
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestJsonPoolCannotDetachAReleasedItem(c *C) {
+  p := NewJson(1, 8)
+  item := p.Checkout()
+  item.Close()
+
+  c.Assert(item.Detach(), IsNil, Commentf("Expecting nothing to be detached"))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+  c.Assert(p.Losses(), Equals, 0, Commentf("Expecting no loss, got %d", p.Losses()))
+}
@@ -8,23 +8,34 @@ This is similar to sync.Pool in (unreleased) Go 1.3
 package bytepool
 
 import (
+  "sync"
   "sync/atomic"
 )
 
 // The pool of byte-slices
 //    misses: count when checkout fails (there's no more slices)
+//    rejected: count of slices given to PutBytes which don't belong to the pool
+//    losses: count of items taken out of the pool by Detach
 //    capacity: size of each slices
 //    list: the pool
+//    lock: protects lent
+//    lent: the items whose slice was handed out by GetBytes and not yet
+//          given back, indexed by the address of their slice
 type Pool struct {
   misses   int32
+  rejected int32
+  losses   int32
   capacity int
   list     chan *Item
+  lock     sync.Mutex
+  lent     map[*byte]*Item
 }
 
 func New(count int, capacity int) *Pool {
   p := &Pool{
     capacity: capacity,
     list:     make(chan *Item, count),
+    lent:     make(map[*byte]*Item),
   }
   for i := 0; i < count; i++ {
     p.list <- newItem(capacity, p)
@@ -32,6 +43,12 @@ func New(count int, capacity int) *Pool {
   return p
 }
 
+// stop managing item (which is checked out) and put a new one in its place
+func (pool *Pool) replace(item *Item) {
+  atomic.AddInt32(&pool.losses, 1)
+  pool.list <- newItem(pool.capacity, pool)
+}
+
 // Get an item out from the pool
 // when there are not enough slices available, it blocks
 // and also increase the misses count
@@ -43,9 +60,43 @@ func (pool *Pool) Checkout() *Item {
     atomic.AddInt32(&pool.misses, 1)
     item = newItem(pool.capacity, nil)
   }
+  atomic.StoreInt32(&item.refs, 1)
   return item
 }
 
+// Get a raw slice out from the pool, it has a length of capacity
+// and should be given back with PutBytes
+func (pool *Pool) GetBytes() []byte {
+  item := pool.Checkout()
+  if item.pool == pool && pool.capacity > 0 {
+    pool.lock.Lock()
+    pool.lent[&item.bytes[0]] = item
+    pool.lock.Unlock()
+  }
+  return item.Raw()
+}
+
+// Give a slice obtained from GetBytes back to the pool
+// slices which aren't currently lent out by GetBytes (foreign ones, the
+// ones created on the fly when the pool was empty, or ones already given
+// back) or whose capacity was changed are counted as rejected and left
+// to the garbage collector
+func (pool *Pool) PutBytes(b []byte) bool {
+  if cap(b) == pool.capacity && cap(b) > 0 {
+    key := &b[:cap(b)][0]
+    pool.lock.Lock()
+    item, ok := pool.lent[key]
+    delete(pool.lent, key)
+    pool.lock.Unlock()
+    if ok {
+      item.Close()
+      return true
+    }
+  }
+  atomic.AddInt32(&pool.rejected, 1)
+  return false
+}
+
 // no of items left inside the pool
 func (pool *Pool) Len() int {
   return len(pool.list)
@@ -54,3 +105,25 @@ func (pool *Pool) Len() int {
 func (pool *Pool) Misses() int {
   return int(atomic.LoadInt32(&pool.misses))
 }
+
+// no of slices given to PutBytes which didn't belong to the pool
+func (pool *Pool) Rejected() int {
+  return int(atomic.LoadInt32(&pool.rejected))
+}
+
+// no of items taken out of the pool by Detach
+func (pool *Pool) Losses() int {
+  return int(atomic.LoadInt32(&pool.losses))
+}
+
+// a snapshot of the pool's counters
+func (pool *Pool) Stats() Stats {
+  return Stats{
+    Capacity:  pool.capacity,
+    Count:     cap(pool.list),
+    Available: pool.Len(),
+    Misses:    pool.Misses(),
+    Rejected:  pool.Rejected(),
+    Losses:    pool.Losses(),
+  }
+}
@@ -2,7 +2,10 @@ package bytepool
 
 import (
   . "gopkg.in/check.v1"
+  "io"
   "reflect"
+  "sync"
+  "sync/atomic"
   "testing"
 )
 
@@ -45,3 +48,153 @@ func (s *TestSuite) TestPoolReleasesAnItemBackIntoThePool(c *C) {
 
   c.Assert(reflect.ValueOf(item2).Pointer(), Equals, pointer, Commentf("Pool returned an unexpected item"))
 }
+
+func (s *TestSuite) TestPoolStats(c *C) {
+  p := New(3, 10)
+  item1 := p.Checkout()
+  item2 := p.Checkout()
+  item3 := p.Checkout()
+  item4 := p.Checkout()
+  item1.Close()
+
+  stats := p.Stats()
+  expected := Stats{Capacity: 10, Count: 3, Available: 1, Misses: 1}
+  c.Assert(stats, Equals, expected, Commentf("Expecting %+v, got %+v", expected, stats))
+
+  item2.Close()
+  item3.Close()
+  item4.Close()
+}
+
+func (s *TestSuite) TestPoolGetsAndPutsRawSlices(c *C) {
+  p := New(1, 20)
+  b := p.GetBytes()
+
+  c.Assert(len(b), Equals, 20, Commentf("Expecting a length of 20, got %d", len(b)))
+  c.Assert(p.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.Len()))
+
+  copy(b, "over 9000")
+  c.Assert(p.PutBytes(b[:0]), Equals, true, Commentf("Expecting the slice to be accepted"))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+
+  item := p.Checkout()
+  defer item.Close()
+  c.Assert(item.Len(), Equals, 0, Commentf("Expecting a length of 0, got %d", item.Len()))
+}
+
+func (s *TestSuite) TestPoolRejectsForeignAndResizedSlices(c *C) {
+  p := New(1, 20)
+  b := p.GetBytes()
+
+  c.Assert(p.PutBytes(make([]byte, 20)), Equals, false, Commentf("Expecting a foreign slice to be rejected"))
+  c.Assert(p.PutBytes(b[1:]), Equals, false, Commentf("Expecting a resliced slice to be rejected"))
+  c.Assert(p.PutBytes(b[:10:10]), Equals, false, Commentf("Expecting a resized slice to be rejected"))
+  c.Assert(p.PutBytes(p.GetBytes()), Equals, false, Commentf("Expecting a dynamically created slice to be rejected"))
+  c.Assert(p.Rejected(), Equals, 4, Commentf("Expecting 4 rejected slices, got %d", p.Rejected()))
+  c.Assert(p.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.Len()))
+
+  p.PutBytes(b)
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestPoolRejectsSlicesPutTwice(c *C) {
+  p := New(1, 20)
+  b := p.GetBytes()
+
+  c.Assert(p.PutBytes(b), Equals, true, Commentf("Expecting the slice to be accepted"))
+  c.Assert(p.PutBytes(b), Equals, false, Commentf("Expecting the second put to be rejected"))
+  c.Assert(p.Rejected(), Equals, 1, Commentf("Expecting 1 rejected slice, got %d", p.Rejected()))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestPoolRejectsStaleSlicesOfCheckedOutItems(c *C) {
+  p := New(1, 4)
+  b := p.GetBytes()
+  p.PutBytes(b)
+  other := p.Checkout()
+  defer other.Close()
+  other.WriteString("over")
+
+  c.Assert(p.PutBytes(b), Equals, false, Commentf("Expecting the stale put to be rejected"))
+  c.Assert(p.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.Len()))
+  copy(p.Checkout().Raw(), "XXXX")
+  c.Assert(other.String(), Equals, "over", Commentf("Expecting %q, got %q", "over", other.String()))
+}
+
+func (s *TestSuite) TestPoolRetainedItemsReturnOnTheLastRelease(c *C) {
+  p := New(1, 20)
+  item := p.Checkout()
+  item.WriteString("over 9000")
+  item.Retain().Retain()
+
+  item.Close()
+  item.Release()
+  c.Assert(p.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.Len()))
+  c.Assert(item.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", item.String()))
+
+  item.Release()
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+  c.Assert(item.Len(), Equals, 0, Commentf("Expecting a length of 0, got %d", item.Len()))
+}
+
+func (s *TestSuite) TestPoolDoesNotDuplicateItemsClosedTwice(c *C) {
+  p := New(2, 20)
+  item := p.Checkout()
+  item.Close()
+  item.Close()
+  item.Release()
+
+  c.Assert(p.Len(), Equals, 2, Commentf("Expecting a pool length of 2, got %d", p.Len()))
+  c.Assert(atomic.LoadInt32(&item.refs), Equals, int32(0))
+  c.Assert(func() { item.Retain() }, PanicMatches, ".*already released")
+  c.Assert(p.Len(), Equals, 2, Commentf("Expecting a pool length of 2, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestPoolRetainedItemsAreSharedBetweenGoroutines(c *C) {
+  p := New(1, 20)
+  item := p.Checkout()
+  item.WriteString("over 9000")
+  var wg sync.WaitGroup
+  for i := 0; i < 10; i++ {
+    wg.Add(1)
+    go func(item *Item) {
+      defer wg.Done()
+      defer item.Release()
+      io.ReadAll(item.NewReader())
+    }(item.Retain())
+  }
+  item.Release()
+  wg.Wait()
+
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestPoolReplacesDetachedItems(c *C) {
+  p := New(1, 20)
+  item := p.Checkout()
+  item.WriteString("over 9000")
+  b := p.GetBytes()
+  detached := item.Detach()
+  item.Close()
+
+  c.Assert(string(detached), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", detached))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+  c.Assert(p.Losses(), Equals, 1, Commentf("Expecting 1 loss, got %d", p.Losses()))
+  c.Assert(p.Stats().Losses, Equals, 1, Commentf("Expecting 1 loss, got %d", p.Stats().Losses))
+
+  replacement := p.GetBytes()
+  c.Assert(&replacement[0] == &item.bytes[0], Equals, false, Commentf("Expecting the detached item not to be reused"))
+  c.Assert(p.PutBytes(replacement), Equals, true, Commentf("Expecting the replacement to belong to the pool"))
+  c.Assert(p.PutBytes(item.Raw()), Equals, false, Commentf("Expecting the detached item not to belong to the pool"))
+  c.Assert(p.PutBytes(b), Equals, false)
+}
+
+func (s *TestSuite) TestPoolCannotDetachAReleasedItem(c *C) {
+  p := New(1, 8)
+  item := p.Checkout()
+  item.Close()
+
+  c.Assert(item.Detach(), IsNil, Commentf("Expecting nothing to be detached"))
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+  c.Assert(p.Losses(), Equals, 0, Commentf("Expecting no loss, got %d", p.Losses()))
+}
@@ -0,0 +1,27 @@
+package bytepool
+
+import (
+  "net/http/httputil"
+)
+
+// Adapts a Pool to the httputil.BufferPool interface used by
+// httputil.ReverseProxy to copy response bodies
+type ProxyBufferPool struct {
+  pool *Pool
+}
+
+var _ httputil.BufferPool = (*ProxyBufferPool)(nil)
+
+func NewProxyBufferPool(pool *Pool) *ProxyBufferPool {
+  return &ProxyBufferPool{pool}
+}
+
+// Get a full slice out from the pool
+func (p *ProxyBufferPool) Get() []byte {
+  return p.pool.GetBytes()
+}
+
+// Return the slice to the pool, see Pool.PutBytes
+func (p *ProxyBufferPool) Put(b []byte) {
+  p.pool.PutBytes(b)
+}
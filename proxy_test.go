@@ -0,0 +1,54 @@
+package bytepool
+
+import (
+  . "gopkg.in/check.v1"
+  "io"
+  "net/http"
+  "net/http/httptest"
+  "net/http/httputil"
+  "net/url"
+  "strings"
+)
+
+func (s *TestSuite) TestProxyBufferPoolHandsOutFullSlices(c *C) {
+  p := NewProxyBufferPool(New(1, 16))
+  b := p.Get()
+
+  c.Assert(len(b), Equals, 16, Commentf("Expecting a length of 16, got %d", len(b)))
+  c.Assert(p.pool.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.pool.Len()))
+
+  p.Put(b)
+  c.Assert(p.pool.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.pool.Len()))
+}
+
+func (s *TestSuite) TestProxyBufferPoolIgnoresForeignSlices(c *C) {
+  p := NewProxyBufferPool(New(1, 16))
+  b := p.Get()
+  p.Put(make([]byte, 16))
+  p.Put(nil)
+
+  c.Assert(p.pool.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.pool.Len()))
+  c.Assert(p.pool.Rejected(), Equals, 2, Commentf("Expecting 2 rejected slices, got %d", p.pool.Rejected()))
+
+  p.Put(b)
+  c.Assert(p.pool.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.pool.Len()))
+}
+
+func (s *TestSuite) TestProxyBufferPoolWorksWithAReverseProxy(c *C) {
+  expected := strings.Repeat("the spice must flow ", 20)
+  backend := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+    io.WriteString(res, expected)
+  }))
+  defer backend.Close()
+
+  target, _ := url.Parse(backend.URL)
+  pool := New(2, 32)
+  proxy := httputil.NewSingleHostReverseProxy(target)
+  proxy.BufferPool = NewProxyBufferPool(pool)
+
+  res := httptest.NewRecorder()
+  proxy.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+  c.Assert(res.Body.String(), Equals, expected, Commentf("Expecting %q, got %q", expected, res.Body.String()))
+  c.Assert(pool.Len(), Equals, 2, Commentf("Expecting a pool length of 2, got %d", pool.Len()))
+}
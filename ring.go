@@ -0,0 +1,142 @@
+package bytepool
+
+import (
+  "io"
+)
+
+// A circular buffer over an item from the pool, for streaming parsers: data
+// is written at the tail and read from the head, and the space freed by reads
+// is reused without copying. Close returns the item to the pool
+//    item: the item providing the buffer
+//    head: position of the first unread byte
+//    size: no of unread bytes
+type Ring struct {
+  item *Item
+  head int
+  size int
+}
+
+func NewRing(pool *Pool) *Ring {
+  return &Ring{item: pool.Checkout()}
+}
+
+// no of unread bytes
+func (r *Ring) Len() int {
+  return r.size
+}
+
+// capacity of the ring
+func (r *Ring) Cap() int {
+  return len(r.item.bytes)
+}
+
+// no of bytes which can be written
+func (r *Ring) Available() int {
+  return len(r.item.bytes) - r.size
+}
+
+// the contiguous unread bytes starting at the head, when the content wraps
+// around, it's only the first part of it (Discard it to get the rest)
+func (r *Ring) ReadableRegion() []byte {
+  end := r.head + r.size
+  if end > len(r.item.bytes) {
+    end = len(r.item.bytes)
+  }
+  return r.item.bytes[r.head:end]
+}
+
+// the contiguous free space starting at the tail, to be written to
+// directly and followed by CommitWrite
+func (r *Ring) WritableRegion() []byte {
+  capacity := len(r.item.bytes)
+  if r.size == capacity {
+    return r.item.bytes[0:0]
+  }
+  if r.size == 0 {
+    r.head = 0
+  }
+  tail := (r.head + r.size) % capacity
+  if tail < r.head {
+    return r.item.bytes[tail:r.head]
+  }
+  return r.item.bytes[tail:capacity]
+}
+
+// mark n bytes written in WritableRegion as part of the content
+// it panics when n is larger than the writable region
+func (r *Ring) CommitWrite(n int) {
+  if n < 0 || n > len(r.WritableRegion()) {
+    panic("bytepool.Ring.CommitWrite: count out of range")
+  }
+  r.size += n
+}
+
+// skip the next n unread bytes, returns io.EOF when fewer were available
+func (r *Ring) Discard(n int) (int, error) {
+  if n > r.size {
+    n = r.size
+    r.discard(n)
+    return n, io.EOF
+  }
+  r.discard(n)
+  return n, nil
+}
+
+func (r *Ring) discard(n int) {
+  if n <= 0 {
+    return
+  }
+  r.size -= n
+  r.head = (r.head + n) % len(r.item.bytes)
+  if r.size == 0 {
+    r.head = 0
+  }
+}
+
+// copy the next unread bytes into p without consuming them
+func (r *Ring) Peek(p []byte) (int, error) {
+  if r.size == 0 {
+    return 0, io.EOF
+  }
+  first := r.ReadableRegion()
+  n := copy(p, first)
+  if n == len(first) && n < r.size {
+    n += copy(p[n:], r.item.bytes[0:r.size-n])
+  }
+  return n, nil
+}
+
+// read (and consume) the next unread bytes into p
+func (r *Ring) Read(p []byte) (int, error) {
+  n, err := r.Peek(p)
+  r.discard(n)
+  return n, err
+}
+
+// write b at the tail, when it doesn't fit, as much as possible
+// is written and io.ErrShortWrite is returned
+func (r *Ring) Write(b []byte) (int, error) {
+  written := 0
+  for written < len(b) {
+    region := r.WritableRegion()
+    if len(region) == 0 {
+      return written, io.ErrShortWrite
+    }
+    n := copy(region, b[written:])
+    r.size += n
+    written += n
+  }
+  return written, nil
+}
+
+// empty the ring
+func (r *Ring) Reset() {
+  r.head = 0
+  r.size = 0
+}
+
+// return the item to the pool, the ring must not be used afterwards
+func (r *Ring) Close() error {
+  r.Reset()
+  return r.item.Close()
+}
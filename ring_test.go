@@ -0,0 +1,83 @@
+package bytepool
+
+import (
+  . "gopkg.in/check.v1"
+  "io"
+)
+
+func (s *TestSuite) TestRingReusesConsumedSpace(c *C) {
+  p := New(1, 8)
+  ring := NewRing(p)
+  ring.Write([]byte("over "))
+  b := make([]byte, 5)
+  ring.Read(b)
+
+  n, err := ring.Write([]byte("9000!!!"))
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, 7, Commentf("Expecting 7 bytes written, got %d", n))
+  c.Assert(ring.Len(), Equals, 7, Commentf("Expecting a length of 7, got %d", ring.Len()))
+  c.Assert(ring.Available(), Equals, 1, Commentf("Expecting 1 available byte, got %d", ring.Available()))
+
+  all, _ := io.ReadAll(ring)
+  c.Assert(string(all), Equals, "9000!!!", Commentf("Expecting %q, got %q", "9000!!!", all))
+
+  ring.Close()
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestRingReportsShortWrites(c *C) {
+  ring := NewRing(New(1, 4))
+  defer ring.Close()
+  n, err := ring.Write([]byte("over 9000"))
+
+  c.Assert(n, Equals, 4, Commentf("Expecting 4 bytes written, got %d", n))
+  c.Assert(err, Equals, io.ErrShortWrite, Commentf("Expecting io.ErrShortWrite, got %v", err))
+}
+
+func (s *TestSuite) TestRingPeeksAcrossTheWrap(c *C) {
+  ring := NewRing(New(1, 6))
+  defer ring.Close()
+  ring.Write([]byte("abcd"))
+  ring.Discard(3)
+  ring.Write([]byte("efgh"))
+
+  c.Assert(string(ring.ReadableRegion()), Equals, "def", Commentf("Expecting %q, got %q", "def", ring.ReadableRegion()))
+  b := make([]byte, 10)
+  n, _ := ring.Peek(b)
+  c.Assert(string(b[:n]), Equals, "defgh", Commentf("Expecting %q, got %q", "defgh", b[:n]))
+  c.Assert(ring.Len(), Equals, 5, Commentf("Expecting Peek not to consume, got a length of %d", ring.Len()))
+}
+
+func (s *TestSuite) TestRingDiscards(c *C) {
+  ring := NewRing(New(1, 6))
+  defer ring.Close()
+  ring.Write([]byte("abcd"))
+
+  n, err := ring.Discard(3)
+  c.Assert(err, IsNil, Commentf("Expecting no error, got %v", err))
+  c.Assert(n, Equals, 3, Commentf("Expecting 3 discarded bytes, got %d", n))
+
+  n, err = ring.Discard(3)
+  c.Assert(err, Equals, io.EOF, Commentf("Expecting io.EOF, got %v", err))
+  c.Assert(n, Equals, 1, Commentf("Expecting 1 discarded byte, got %d", n))
+}
+
+func (s *TestSuite) TestRingWritableRegionCanBeWrittenToDirectly(c *C) {
+  ring := NewRing(New(1, 6))
+  defer ring.Close()
+  ring.Write([]byte("abcd"))
+  ring.Discard(2)
+
+  region := ring.WritableRegion()
+  c.Assert(len(region), Equals, 2, Commentf("Expecting a region of 2 bytes, got %d", len(region)))
+  ring.CommitWrite(copy(region, "ef"))
+
+  region = ring.WritableRegion()
+  c.Assert(len(region), Equals, 2, Commentf("Expecting the region to wrap around, got %d bytes", len(region)))
+  ring.CommitWrite(copy(region, "gh"))
+  c.Assert(len(ring.WritableRegion()), Equals, 0, Commentf("Expecting the ring to be full"))
+  c.Assert(func() { ring.CommitWrite(1) }, PanicMatches, ".*out of range")
+
+  all, _ := io.ReadAll(ring)
+  c.Assert(string(all), Equals, "cdefgh", Commentf("Expecting %q, got %q", "cdefgh", all))
+}
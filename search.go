@@ -0,0 +1,51 @@
+package bytepool
+
+import (
+  "bytes"
+)
+
+// The search methods work on the content (Bytes()) like their counterparts
+// from the bytes package, without copying it. Use Unread() to search the
+// part which hasn't been read yet
+
+// the content which hasn't been read yet, only valid until the next write
+func (item *Item) Unread() []byte {
+  return item.bytes[item.read:item.length]
+}
+
+func (item *Item) Index(sep []byte) int {
+  return bytes.Index(item.Bytes(), sep)
+}
+
+func (item *Item) IndexByte(c byte) int {
+  return bytes.IndexByte(item.Bytes(), c)
+}
+
+func (item *Item) LastIndex(sep []byte) int {
+  return bytes.LastIndex(item.Bytes(), sep)
+}
+
+func (item *Item) Contains(sub []byte) bool {
+  return bytes.Contains(item.Bytes(), sub)
+}
+
+func (item *Item) HasPrefix(prefix []byte) bool {
+  return bytes.HasPrefix(item.Bytes(), prefix)
+}
+
+func (item *Item) HasSuffix(suffix []byte) bool {
+  return bytes.HasSuffix(item.Bytes(), suffix)
+}
+
+func (item *Item) Equal(b []byte) bool {
+  return bytes.Equal(item.Bytes(), b)
+}
+
+// tell whether the content and b are equal under Unicode case-folding
+func (item *Item) EqualFold(b []byte) bool {
+  return bytes.EqualFold(item.Bytes(), b)
+}
+
+func (item *Item) Count(sep []byte) int {
+  return bytes.Count(item.Bytes(), sep)
+}
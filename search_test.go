@@ -0,0 +1,51 @@
+package bytepool
+
+import (
+  . "gopkg.in/check.v1"
+  "testing"
+)
+
+func (s *TestSuite) TestSearchesTheContent(c *C) {
+  item := newItem(40, nil)
+  item.WriteString("GET /users/9000 HTTP/1.1")
+
+  c.Assert(item.Index([]byte("/")), Equals, 4)
+  c.Assert(item.IndexByte(' '), Equals, 3)
+  c.Assert(item.LastIndex([]byte("/")), Equals, 20)
+  c.Assert(item.Count([]byte("/")), Equals, 3)
+  c.Assert(item.Contains([]byte("users")), Equals, true)
+  c.Assert(item.Contains([]byte("admins")), Equals, false)
+  c.Assert(item.HasPrefix([]byte("GET ")), Equals, true)
+  c.Assert(item.HasSuffix([]byte("HTTP/1.1")), Equals, true)
+  c.Assert(item.HasSuffix([]byte("HTTP/2")), Equals, false)
+}
+
+func (s *TestSuite) TestComparesTheContent(c *C) {
+  item := newItem(40, nil)
+  item.WriteString("Over 9000")
+
+  c.Assert(item.Equal([]byte("Over 9000")), Equals, true)
+  c.Assert(item.Equal([]byte("over 9000")), Equals, false)
+  c.Assert(item.EqualFold([]byte("OVER 9000")), Equals, true)
+}
+
+func (s *TestSuite) TestUnreadIsTheContentNotReadYet(c *C) {
+  item := newItem(40, nil)
+  item.WriteString("over 9000")
+  item.Next(5)
+
+  c.Assert(string(item.Unread()), Equals, "9000")
+  c.Assert(item.Index([]byte("9")), Equals, 5, Commentf("Expecting searches to cover the whole content"))
+}
+
+func (s *TestSuite) TestSearchingDoesNotAllocate(c *C) {
+  item := newItem(40, nil)
+  item.WriteString("GET /users/9000 HTTP/1.1")
+  allocs := testing.AllocsPerRun(100, func() {
+    item.HasPrefix([]byte("GET "))
+    item.Contains([]byte("users"))
+    item.EqualFold([]byte("get"))
+  })
+
+  c.Assert(allocs, Equals, float64(0), Commentf("Expecting no allocation, got %v", allocs))
+}
@@ -0,0 +1,79 @@
+package bytepool
+
+import (
+  "io"
+)
+
+// A read-only view over part of an item's content which holds a reference
+// to the item, keeping it out of the pool until the slice is released
+//    owner: the item (or JsonItem) holding the reference, nil once released
+//    bytes: the viewed content
+type Slice struct {
+  owner io.Closer
+  bytes []byte
+}
+
+// create a view over content[i:j], it panics when i or j are out of range
+func (item *Item) Slice(i, j int) *Slice {
+  bytes := item.view(i, j)
+  return &Slice{
+    owner: item.Retain(),
+    bytes: bytes,
+  }
+}
+
+// create a view over content[i:j] which returns the JsonItem to its pool
+// when it's the last reference to be released, see Item.Slice
+func (item *JsonItem) Slice(i, j int) *Slice {
+  item.TrimLastIf(',')
+  bytes := item.view(i, j)
+  return &Slice{
+    owner: item.Retain(),
+    bytes: bytes,
+  }
+}
+
+// content[i:j] with its capacity limited to its length
+func (item *Item) view(i, j int) []byte {
+  if i < 0 || j < i || j > item.length {
+    panic("bytepool.Item.Slice: slice bounds out of range")
+  }
+  return item.bytes[i:j:j]
+}
+
+// the viewed content, it must not be modified
+func (s *Slice) Bytes() []byte {
+  return s.bytes
+}
+
+func (s *Slice) String() string {
+  return string(s.bytes)
+}
+
+func (s *Slice) Len() int {
+  return len(s.bytes)
+}
+
+// write the viewed content to w
+func (s *Slice) WriteTo(w io.Writer) (int64, error) {
+  n, err := w.Write(s.bytes)
+  if err == nil && n < len(s.bytes) {
+    err = io.ErrShortWrite
+  }
+  return int64(n), err
+}
+
+// drop the slice's reference to the item, the slice must not be used afterwards
+func (s *Slice) Release() {
+  if s.owner != nil {
+    s.owner.Close()
+    s.owner = nil
+    s.bytes = nil
+  }
+}
+
+// same as Release
+func (s *Slice) Close() error {
+  s.Release()
+  return nil
+}
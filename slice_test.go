@@ -0,0 +1,50 @@
+package bytepool
+
+import (
+  "bytes"
+  . "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestSliceKeepsTheItemOutOfThePool(c *C) {
+  p := New(1, 20)
+  item := p.Checkout()
+  item.WriteString("over 9000")
+  slice := item.Slice(5, 9)
+  item.Close()
+
+  c.Assert(p.Len(), Equals, 0, Commentf("Expecting a pool length of 0, got %d", p.Len()))
+  c.Assert(slice.String(), Equals, "9000", Commentf("Expecting %q, got %q", "9000", slice.String()))
+  c.Assert(slice.Len(), Equals, 4, Commentf("Expecting a length of 4, got %d", slice.Len()))
+
+  slice.Release()
+  slice.Release()
+  c.Assert(p.Len(), Equals, 1, Commentf("Expecting a pool length of 1, got %d", p.Len()))
+}
+
+func (s *TestSuite) TestSliceCannotBeAppendedToOverTheItem(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  slice := item.Slice(0, 4)
+  _ = append(slice.Bytes(), '!')
+
+  c.Assert(item.String(), Equals, "over 9000", Commentf("Expecting %q, got %q", "over 9000", item.String()))
+}
+
+func (s *TestSuite) TestSliceWritesTo(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over 9000")
+  slice := item.Slice(0, 4)
+  defer slice.Release()
+  buffer := new(bytes.Buffer)
+  slice.WriteTo(buffer)
+
+  c.Assert(buffer.String(), Equals, "over", Commentf("Expecting %q, got %q", "over", buffer.String()))
+}
+
+func (s *TestSuite) TestSliceOutOfRangePanics(c *C) {
+  item := newItem(20, nil)
+  item.WriteString("over")
+
+  c.Assert(func() { item.Slice(2, 5) }, PanicMatches, ".*out of range")
+  c.Assert(func() { item.Slice(3, 2) }, PanicMatches, ".*out of range")
+}